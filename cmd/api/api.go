@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fmt"
+	"html/template"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -10,21 +17,65 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/marekh19/uptime-ume/docs"
+	"github.com/marekh19/uptime-ume/internal/changefeed"
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/domains"
+	"github.com/marekh19/uptime-ume/internal/notify"
+	"github.com/marekh19/uptime-ume/internal/ratelimit"
+	"github.com/marekh19/uptime-ume/internal/results"
+	"github.com/marekh19/uptime-ume/internal/retention"
+	"github.com/marekh19/uptime-ume/internal/scheduler"
+	"github.com/marekh19/uptime-ume/internal/signer"
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/statuspage"
 	"github.com/marekh19/uptime-ume/internal/store"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
 type application struct {
-	store  store.Storage
-	logger *zap.SugaredLogger
-	config config
+	store       store.Storage
+	kinds       *checker.Registry
+	feed        *changefeed.Feed
+	push        *checker.PushChecker
+	scheduler   *scheduler.Scheduler
+	stats       *stats.Calculator
+	statusPages *statuspage.Builder
+	notifier    *notify.Dispatcher
+	signer      *signer.Signer
+	domains     *domains.Verifier
+	templates   map[string]*template.Template
+	logger      *zap.SugaredLogger
+	config      config
+
+	// loginsPerIP and loginsPerPage throttle password guesses on status
+	// pages.
+	loginsPerIP   *ratelimit.Limiter
+	loginsPerPage *ratelimit.Limiter
+	// subscriptionsPerIP throttles visitors subscribing to status pages.
+	subscriptionsPerIP *ratelimit.Limiter
 }
 
 type config struct {
-	addr   string
-	env    string
-	apiURL string
-	db     dbConfig
+	addr               string
+	env                string
+	apiURL             string
+	db                 dbConfig
+	changefeedCapacity int
+	results            results.BatchConfig
+	retention          retention.Policy
+	publicURL          string
+	secretKey          string
+	smtp               notify.SMTPConfig
+	// dnsResolver is the host:port of the DNS server custom domains are
+	// verified with, or empty for the system resolver.
+	dnsResolver string
+	// adminAddr is where runtime metrics are served, apart from the API so
+	// that they are not public, or empty to not serve them.
+	adminAddr string
+	// trustProxyHeaders makes IP allowlists check the client address
+	// passed on by a reverse proxy in X-Forwarded-For or X-Real-IP, which
+	// clients connecting directly could forge.
+	trustProxyHeaders bool
 }
 
 type dbConfig struct {
@@ -38,15 +89,51 @@ func (app *application) mount() http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
+	r.Use(peerAddrMiddleware)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(app.customDomainMiddleware)
 
 	// Set a timeout value on the request context (ctx), that will signal
 	// through ctx.Done() that the request has timed out and further
 	// processing should be stopped.
 	r.Use(middleware.Timeout(60 * time.Second))
 
+	// Public status pages
+	r.Route("/status/{slug}", func(r chi.Router) {
+		r.Use(app.publicStatusPageMiddleware(true))
+
+		r.Post("/login", app.statusPageLoginHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.statusPageAccessMiddleware(true))
+
+			r.Get("/", app.statusPageHTMLHandler)
+			r.Post("/subscribe", app.subscribeHTMLHandler)
+			r.Get("/feed.atom", app.atomFeedHandler)
+			r.Get("/feed.rss", app.rssFeedHandler)
+			r.Get("/badge.svg", app.statusPageBadgeHandler)
+			r.Get("/badge.json", app.statusPageBadgeHandler)
+		})
+	})
+
+	// Badges of monitors that have them enabled
+	r.Route("/badge/{monitorId}", func(r chi.Router) {
+		r.Use(app.badgeMonitorContextMiddleware)
+
+		r.Get("/{badge}", app.monitorBadgeHandler)
+	})
+
+	// Signed links sent to subscribers
+	r.Route("/subscriptions/{subscriberId}", func(r chi.Router) {
+		r.Use(app.subscriptionLinkMiddleware)
+
+		r.Get("/confirm", app.confirmSubscriptionHandler)
+		r.Get("/unsubscribe", app.unsubscribeHandler)
+		r.Post("/unsubscribe", app.unsubscribeHandler)
+	})
+
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Get("/health", app.healthCheckHandler)
@@ -64,6 +151,76 @@ func (app *application) mount() http.Handler {
 					r.Get("/", app.getMonitorHandler)
 					r.Delete("/", app.deleteMonitorHandler)
 					r.Patch("/", app.updateMonitorHandler)
+					r.Get("/certificate", app.getMonitorCertificateHandler)
+					r.Get("/incidents", app.listMonitorIncidentsHandler)
+					r.Get("/results", app.listMonitorResultsHandler)
+					r.Get("/stats", app.getMonitorStatsHandler)
+				})
+			})
+
+			r.Get("/incidents", app.listIncidentsHandler)
+
+			r.Route("/public/status-pages/{slug}", func(r chi.Router) {
+				r.Use(app.publicStatusPageMiddleware(false))
+				r.Use(app.statusPageAccessMiddleware(false))
+
+				r.Get("/", app.getPublicStatusPageHandler)
+				r.Post("/subscriptions", app.createSubscriptionHandler)
+			})
+
+			r.Route("/status-pages", func(r chi.Router) {
+				r.Post("/", app.createStatusPageHandler)
+				r.Get("/", app.listStatusPagesHandler)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Use(app.statusPageContextMiddleware)
+
+					r.Get("/", app.getStatusPageHandler)
+					r.Patch("/", app.updateStatusPageHandler)
+					r.Delete("/", app.deleteStatusPageHandler)
+					r.Post("/subscribers", app.createSubscriberHandler)
+					r.Get("/subscribers", app.listSubscribersHandler)
+					r.Delete("/subscribers/{subscriberId}", app.deleteSubscriberHandler)
+					r.Get("/domain", app.getStatusPageDomainHandler)
+					r.Post("/domain/verify", app.verifyStatusPageDomainHandler)
+
+					r.Route("/groups", func(r chi.Router) {
+						r.Post("/", app.createComponentGroupHandler)
+						r.Get("/", app.listComponentGroupsHandler)
+						r.Route("/{groupId}", func(r chi.Router) {
+							r.Use(app.componentGroupContextMiddleware)
+
+							r.Get("/", app.getComponentGroupHandler)
+							r.Patch("/", app.updateComponentGroupHandler)
+							r.Delete("/", app.deleteComponentGroupHandler)
+						})
+					})
+
+					r.Route("/components", func(r chi.Router) {
+						r.Post("/", app.createComponentHandler)
+						r.Get("/", app.listComponentsHandler)
+						r.Route("/{componentId}", func(r chi.Router) {
+							r.Use(app.componentContextMiddleware)
+
+							r.Get("/", app.getComponentHandler)
+							r.Patch("/", app.updateComponentHandler)
+							r.Delete("/", app.deleteComponentHandler)
+							r.Put("/override", app.setComponentOverrideHandler)
+							r.Delete("/override", app.deleteComponentOverrideHandler)
+						})
+					})
+
+					r.Route("/posts", func(r chi.Router) {
+						r.Post("/", app.createPostHandler)
+						r.Get("/", app.listPostsHandler)
+						r.Route("/{postId}", func(r chi.Router) {
+							r.Use(app.postContextMiddleware)
+
+							r.Get("/", app.getPostHandler)
+							r.Patch("/", app.updatePostHandler)
+							r.Delete("/", app.deletePostHandler)
+							r.Post("/updates", app.createPostUpdateHandler)
+						})
+					})
 				})
 			})
 
@@ -71,12 +228,29 @@ func (app *application) mount() http.Handler {
 			r.Route("/auth", func(r chi.Router) {
 				r.Post("/register", app.registerUserHandler)
 			})
+
+			r.Route("/push/{token}", func(r chi.Router) {
+				r.Get("/", app.pushHandler)
+				r.Post("/", app.pushHandler)
+			})
 		})
 	})
 
 	return r
 }
 
+// mountAdmin returns the routes of the admin listener.
+func (app *application) mountAdmin() http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+
+	// Runtime metrics, such as the number of pruned results
+	r.Get("/debug/vars", expvar.Handler().ServeHTTP)
+
+	return r
+}
+
 func (app *application) run(mux http.Handler) error {
 	// Docs
 	docs.SwaggerInfo.Version = version
@@ -91,7 +265,55 @@ func (app *application) run(mux http.Handler) error {
 		IdleTimeout:  time.Minute,
 	}
 
+	var admin *http.Server
+	if app.config.adminAddr != "" {
+		admin = &http.Server{
+			Addr:         app.config.adminAddr,
+			Handler:      app.mountAdmin(),
+			WriteTimeout: time.Second * 30,
+			ReadTimeout:  time.Second * 10,
+			IdleTimeout:  time.Minute,
+		}
+
+		go func() {
+			app.logger.Infow("Admin server has started", "addr", app.config.adminAddr)
+
+			if err := admin.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				app.logger.Errorw("Admin server has stopped", "error", err.Error())
+			}
+		}()
+	}
+
+	shutdown := make(chan error)
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		s := <-quit
+
+		app.logger.Infow("Signal caught, shutting down", "signal", s.String())
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if admin != nil {
+			admin.Shutdown(ctx)
+		}
+
+		shutdown <- srv.Shutdown(ctx)
+	}()
+
 	app.logger.Infow("Server has started", "addr", app.config.addr, "env", app.config.env)
 
-	return srv.ListenAndServe()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	if err := <-shutdown; err != nil {
+		return err
+	}
+
+	app.logger.Infow("Server has stopped", "addr", app.config.addr)
+
+	return nil
 }
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/badge"
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/statuspage"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// badgeCacheSeconds is how long badges may be cached by browsers and by
+// image proxies such as the one in front of GitHub READMEs.
+const badgeCacheSeconds = 60
+
+// ShieldsEndpoint is the response of JSON badges, in the format of the
+// shields.io endpoint badge.
+type ShieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	LabelColor    string `json:"labelColor,omitempty"`
+	Style         string `json:"style,omitempty"`
+	CacheSeconds  int    `json:"cacheSeconds"`
+}
+
+// MonitorBadge godoc
+//
+//	@Summary		Get Monitor Badge
+//	@Description	Get a badge with the status of a monitor (status.svg, status.json) or its uptime over a standard window (uptime-30d.svg, uptime-24h.json, ...). SVG badges are images, JSON badges follow the shields.io endpoint format. Only monitors with badges enabled have badges.
+//	@Tags			public
+//	@Produce		image/svg+xml
+//	@Produce		json
+//	@Param			monitorId	path		string	true	"Monitor ID"
+//	@Param			badge		path		string	true	"Badge file name, such as status.svg or uptime-30d.json"
+//	@Param			label		query		string	false	"Text on the left"
+//	@Param			color		query		string	false	"Color of the message, a name or hex value"
+//	@Param			labelColor	query		string	false	"Color of the label, a name or hex value"
+//	@Param			style		query		string	false	"flat, flat-square, plastic or for-the-badge"
+//	@Success		200			{object}	main.ShieldsEndpoint
+//	@Failure		400			{object}	error
+//	@Failure		404			{object}	error
+//	@Failure		500			{object}	error
+//	@Router			/badge/{monitorId}/{badge} [get]
+func (app *application) monitorBadgeHandler(w http.ResponseWriter, r *http.Request) {
+	monitor := getMonitorFromContext(r)
+
+	name := chi.URLParam(r, "badge")
+	format := path.Ext(name)
+	kind := strings.TrimSuffix(name, format)
+
+	var b badge.Badge
+
+	switch {
+	case kind == "status":
+		status, err := app.monitorStatus(r.Context(), monitor.ID)
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		b = statusBadge(status)
+
+	case strings.HasPrefix(kind, "uptime-"):
+		window, ok := stats.LookupWindow(strings.TrimPrefix(kind, "uptime-"))
+		if !ok {
+			app.notFoundError(w, r, fmt.Errorf("unknown badge %s", name))
+			return
+		}
+
+		result, err := app.stats.Window(r.Context(), monitor.ID, window, time.Now())
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		b = uptimeBadge(window, result.Uptime)
+
+	default:
+		app.notFoundError(w, r, fmt.Errorf("unknown badge %s", name))
+		return
+	}
+
+	app.writeBadge(w, r, format, b)
+}
+
+// statusPageBadgeHandler serves the badge with the overall status of a
+// public status page, as SVG or shields.io JSON depending on the extension.
+func (app *application) statusPageBadgeHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	// The badge shows the status of the page as visitors see it, taken
+	// from its components when it has any.
+	view, err := app.statusPages.Build(r.Context(), statusPage, time.Now())
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	app.writeBadge(w, r, path.Ext(r.URL.Path), statusBadge(view.Status))
+}
+
+// monitorStatus returns the latest confirmed status of a monitor.
+func (app *application) monitorStatus(ctx context.Context, monitorID string) (string, error) {
+	latest, err := app.store.PingResults.GetLatestConfirmed(ctx, monitorID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return statuspage.StatusUnknown, nil
+		}
+		return "", err
+	}
+
+	return latest.Status, nil
+}
+
+func statusBadge(status string) badge.Badge {
+	b := badge.Badge{Label: "status", Message: status}
+
+	switch status {
+	case checker.StatusUp:
+		b.Color = "brightgreen"
+	case checker.StatusDegraded:
+		b.Color = "yellow"
+	case checker.StatusDown:
+		b.Color = "red"
+	case statuspage.StatusMaintenance:
+		b.Color = "blue"
+	default:
+		b.Color = "lightgrey"
+	}
+
+	return b
+}
+
+func uptimeBadge(window stats.Window, uptime *float64) badge.Badge {
+	b := badge.Badge{Label: "uptime " + window.Name, Message: "no data", Color: "lightgrey"}
+	if uptime == nil {
+		return b
+	}
+
+	b.Message = fmt.Sprintf("%.2f%%", *uptime)
+
+	switch {
+	case *uptime >= 99.9:
+		b.Color = "brightgreen"
+	case *uptime >= 99:
+		b.Color = "green"
+	case *uptime >= 97:
+		b.Color = "yellowgreen"
+	case *uptime >= 95:
+		b.Color = "yellow"
+	case *uptime >= 90:
+		b.Color = "orange"
+	default:
+		b.Color = "red"
+	}
+
+	return b
+}
+
+// writeBadge applies the label, color, labelColor and style query
+// parameters to b and writes it as SVG or shields.io JSON.
+func (app *application) writeBadge(w http.ResponseWriter, r *http.Request, format string, b badge.Badge) {
+	query := r.URL.Query()
+
+	if query.Has("label") {
+		b.Label = query.Get("label")
+	}
+
+	colors := []struct {
+		param string
+		field *string
+	}{
+		{"color", &b.Color},
+		{"labelColor", &b.LabelColor},
+	}
+	for _, color := range colors {
+		if value := query.Get(color.param); value != "" {
+			if _, ok := badge.Color(value); !ok {
+				app.badRequestError(w, r, fmt.Errorf("%s must be a color name or hex value", color.param))
+				return
+			}
+			*color.field = value
+		}
+	}
+
+	if b.Style = query.Get("style"); b.Style != "" && !slices.Contains(badge.Styles, b.Style) {
+		app.badRequestError(w, r, fmt.Errorf("style must be one of %v", badge.Styles))
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", badgeCacheSeconds))
+
+	switch format {
+	case ".svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b.SVG())
+
+	case ".json":
+		endpoint := ShieldsEndpoint{
+			SchemaVersion: 1,
+			Label:         b.Label,
+			Message:       b.Message,
+			Color:         strings.TrimPrefix(b.Color, "#"),
+			LabelColor:    strings.TrimPrefix(b.LabelColor, "#"),
+			Style:         b.Style,
+			CacheSeconds:  badgeCacheSeconds,
+		}
+
+		if err := writeJSON(w, http.StatusOK, endpoint); err != nil {
+			app.internalServerError(w, r, err)
+		}
+
+	default:
+		app.notFoundError(w, r, fmt.Errorf("unknown badge format %q", format))
+	}
+}
+
+// badgeMonitorContextMiddleware loads the monitor of a badge. Monitors
+// without badges enabled are reported as not found, so that badges do not
+// reveal which monitors exist.
+func (app *application) badgeMonitorContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		monitor, err := app.store.Monitors.GetByID(ctx, chi.URLParam(r, "monitorId"))
+		if err == nil && !monitor.BadgeEnabled {
+			err = store.ErrNotFound
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		ctx = context.WithValue(ctx, monitorCtx, monitor)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
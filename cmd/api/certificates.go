@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// GetMonitorCertificate godoc
+//
+//	@Summary		Get Monitor Certificate
+//	@Description	Get the TLS certificate seen by the latest check of a monitor
+//	@Tags			monitors
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Monitor ID"
+//	@Success		200	{object}	store.Certificate
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/monitors/{id}/certificate [get]
+func (app *application) getMonitorCertificateHandler(w http.ResponseWriter, r *http.Request) {
+	monitor := getMonitorFromContext(r)
+
+	certificate, err := app.store.Certificates.GetByMonitorID(r.Context(), monitor.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, certificate); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
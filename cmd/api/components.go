@@ -0,0 +1,656 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type componentKey string
+
+const (
+	componentGroupCtx componentKey = "componentGroup"
+	componentCtx      componentKey = "component"
+)
+
+type CreateComponentGroupPayload struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CreateComponentGroup godoc
+//
+//	@Summary		Create Component Group
+//	@Description	Add a named group of components to a status page, after its other groups
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Status Page ID"
+//	@Param			payload	body		main.CreateComponentGroupPayload	true	"CreateComponentGroupPayload"
+//	@Success		201		{object}	store.ComponentGroup
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/groups [post]
+func (app *application) createComponentGroupHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	var payload CreateComponentGroupPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	group := &store.ComponentGroup{
+		ID:           id,
+		StatusPageID: statusPage.ID,
+		Name:         payload.Name,
+	}
+
+	if err := app.store.ComponentGroups.Create(r.Context(), group); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, group); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListComponentGroups godoc
+//
+//	@Summary		List Component Groups
+//	@Description	List the component groups of a status page in the order they are shown
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{array}		store.ComponentGroup
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/groups [get]
+func (app *application) listComponentGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	groups, err := app.store.ComponentGroups.List(r.Context(), statusPage.ID)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, groups); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetComponentGroup godoc
+//
+//	@Summary		Get Component Group
+//	@Description	Get a component group of a status page
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Status Page ID"
+//	@Param			groupId	path		string	true	"Component Group ID"
+//	@Success		200		{object}	store.ComponentGroup
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/groups/{groupId} [get]
+func (app *application) getComponentGroupHandler(w http.ResponseWriter, r *http.Request) {
+	group := getComponentGroupFromContext(r)
+
+	if err := app.jsonResponse(w, http.StatusOK, group); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+type UpdateComponentGroupPayload struct {
+	Name     *string `json:"name" validate:"omitempty,max=100"`
+	Position *int    `json:"position" validate:"omitempty,gte=0"`
+}
+
+// UpdateComponentGroup godoc
+//
+//	@Summary		Update Component Group
+//	@Description	Rename a component group, or move it by changing its position. Groups are shown in ascending order of position.
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Status Page ID"
+//	@Param			groupId	path		string								true	"Component Group ID"
+//	@Param			payload	body		main.UpdateComponentGroupPayload	true	"UpdateComponentGroupPayload"
+//	@Success		200		{object}	store.ComponentGroup
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/groups/{groupId} [patch]
+func (app *application) updateComponentGroupHandler(w http.ResponseWriter, r *http.Request) {
+	group := getComponentGroupFromContext(r)
+
+	var payload UpdateComponentGroupPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if payload.Name != nil {
+		group.Name = *payload.Name
+	}
+
+	if payload.Position != nil {
+		group.Position = *payload.Position
+	}
+
+	if err := app.store.ComponentGroups.Update(r.Context(), group); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, group); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// DeleteComponentGroup godoc
+//
+//	@Summary		Delete Component Group
+//	@Description	Delete a component group. Its components are kept outside of any group.
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Status Page ID"
+//	@Param			groupId	path	string	true	"Component Group ID"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/groups/{groupId} [delete]
+func (app *application) deleteComponentGroupHandler(w http.ResponseWriter, r *http.Request) {
+	group := getComponentGroupFromContext(r)
+
+	if err := app.store.ComponentGroups.Delete(r.Context(), group.ID); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type CreateComponentPayload struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Description string  `json:"description" validate:"max=500"`
+	GroupID     *string `json:"group_id"`
+	// Rule rolls up the statuses of the monitors: all, any or majority.
+	// Defaults to all.
+	Rule       string   `json:"rule" validate:"omitempty,oneof=all any majority"`
+	MonitorIDs []string `json:"monitors" validate:"unique,dive,required"`
+}
+
+// CreateComponent godoc
+//
+//	@Summary		Create Component
+//	@Description	Add a component backed by monitors of a status page, after its other components
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Status Page ID"
+//	@Param			payload	body		main.CreateComponentPayload	true	"CreateComponentPayload"
+//	@Success		201		{object}	store.Component
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components [post]
+func (app *application) createComponentHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	var payload CreateComponentPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	component := &store.Component{
+		ID:           id,
+		StatusPageID: statusPage.ID,
+		GroupID:      payload.GroupID,
+		Name:         payload.Name,
+		Description:  payload.Description,
+		Rule:         payload.Rule,
+		MonitorIDs:   payload.MonitorIDs,
+	}
+	if component.Rule == "" {
+		component.Rule = store.RuleAll
+	}
+	if component.MonitorIDs == nil {
+		component.MonitorIDs = []string{}
+	}
+
+	ctx := r.Context()
+
+	if fields, err := app.validateComponent(ctx, component, statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	} else if len(fields) > 0 {
+		app.failedValidationError(w, r, errors.New("invalid component"), fields)
+		return
+	}
+
+	if err := app.store.Components.Create(ctx, component); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, component); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListComponents godoc
+//
+//	@Summary		List Components
+//	@Description	List the components of a status page in the order they are shown within their groups
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{array}		store.Component
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components [get]
+func (app *application) listComponentsHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	components, err := app.store.Components.List(r.Context(), statusPage.ID)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, components); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetComponent godoc
+//
+//	@Summary		Get Component
+//	@Description	Get a component of a status page
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"Status Page ID"
+//	@Param			componentId	path		string	true	"Component ID"
+//	@Success		200			{object}	store.Component
+//	@Failure		404			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components/{componentId} [get]
+func (app *application) getComponentHandler(w http.ResponseWriter, r *http.Request) {
+	component := getComponentFromContext(r)
+
+	if err := app.jsonResponse(w, http.StatusOK, component); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+type UpdateComponentPayload struct {
+	Name        *string `json:"name" validate:"omitempty,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	// GroupID moves the component to another group. An empty ID moves it
+	// out of its group.
+	GroupID    *string  `json:"group_id"`
+	Position   *int     `json:"position" validate:"omitempty,gte=0"`
+	Rule       *string  `json:"rule" validate:"omitempty,oneof=all any majority"`
+	MonitorIDs []string `json:"monitors" validate:"omitempty,unique,dive,required"`
+}
+
+// UpdateComponent godoc
+//
+//	@Summary		Update Component
+//	@Description	Update a component, or move it by changing its group or position. Monitors, when given, replace the backing ones.
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Status Page ID"
+//	@Param			componentId	path		string						true	"Component ID"
+//	@Param			payload		body		main.UpdateComponentPayload	true	"UpdateComponentPayload"
+//	@Success		200			{object}	store.Component
+//	@Failure		400			{object}	error
+//	@Failure		404			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components/{componentId} [patch]
+func (app *application) updateComponentHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+	component := getComponentFromContext(r)
+
+	var payload UpdateComponentPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if payload.Name != nil {
+		component.Name = *payload.Name
+	}
+
+	if payload.Description != nil {
+		component.Description = *payload.Description
+	}
+
+	if payload.GroupID != nil {
+		component.GroupID = payload.GroupID
+		if *payload.GroupID == "" {
+			component.GroupID = nil
+		}
+	}
+
+	if payload.Position != nil {
+		component.Position = *payload.Position
+	}
+
+	if payload.Rule != nil {
+		component.Rule = *payload.Rule
+	}
+
+	if payload.MonitorIDs != nil {
+		component.MonitorIDs = payload.MonitorIDs
+	}
+
+	ctx := r.Context()
+
+	if fields, err := app.validateComponent(ctx, component, statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	} else if len(fields) > 0 {
+		app.failedValidationError(w, r, errors.New("invalid component"), fields)
+		return
+	}
+
+	app.saveComponent(w, r, component)
+}
+
+type SetComponentOverridePayload struct {
+	Status  string `json:"status" validate:"required,oneof=up degraded down maintenance"`
+	Message string `json:"message" validate:"max=500"`
+	// ExpiresAt is when the component goes back to the status of its
+	// monitors. Without it, the override lasts until removed.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// SetComponentOverride godoc
+//
+//	@Summary		Set Component Override
+//	@Description	Show a status of your choosing for a component instead of the one of its monitors, until the override expires or is removed
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string								true	"Status Page ID"
+//	@Param			componentId	path		string								true	"Component ID"
+//	@Param			payload		body		main.SetComponentOverridePayload	true	"SetComponentOverridePayload"
+//	@Success		200			{object}	store.Component
+//	@Failure		400			{object}	error
+//	@Failure		404			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components/{componentId}/override [put]
+func (app *application) setComponentOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	component := getComponentFromContext(r)
+
+	var payload SetComponentOverridePayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+		app.failedValidationError(w, r, errors.New("override has expired"), map[string]string{
+			"expires_at": "must be in the future",
+		})
+		return
+	}
+
+	component.Override = &store.ComponentOverride{
+		Status:    payload.Status,
+		Message:   payload.Message,
+		ExpiresAt: payload.ExpiresAt,
+	}
+
+	app.saveComponent(w, r, component)
+}
+
+// DeleteComponentOverride godoc
+//
+//	@Summary		Delete Component Override
+//	@Description	Show the status of the monitors of a component again
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"Status Page ID"
+//	@Param			componentId	path		string	true	"Component ID"
+//	@Success		200			{object}	store.Component
+//	@Failure		404			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components/{componentId}/override [delete]
+func (app *application) deleteComponentOverrideHandler(w http.ResponseWriter, r *http.Request) {
+	component := getComponentFromContext(r)
+	component.Override = nil
+
+	app.saveComponent(w, r, component)
+}
+
+// saveComponent stores the changes to component and responds with it.
+func (app *application) saveComponent(w http.ResponseWriter, r *http.Request, component *store.Component) {
+	if err := app.store.Components.Update(r.Context(), component); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, component); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// DeleteComponent godoc
+//
+//	@Summary		Delete Component
+//	@Description	Delete a component of a status page
+//	@Tags			components
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path	string	true	"Status Page ID"
+//	@Param			componentId	path	string	true	"Component ID"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/components/{componentId} [delete]
+func (app *application) deleteComponentHandler(w http.ResponseWriter, r *http.Request) {
+	component := getComponentFromContext(r)
+
+	if err := app.store.Components.Delete(r.Context(), component.ID); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateComponent checks that the group of component is one of the status
+// page's, and that its monitors are shown on the page.
+func (app *application) validateComponent(ctx context.Context, component *store.Component, statusPage *store.StatusPage) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	if component.GroupID != nil {
+		group, err := app.store.ComponentGroups.GetByID(ctx, *component.GroupID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return nil, err
+		}
+
+		if group == nil || group.StatusPageID != statusPage.ID {
+			fields["group_id"] = "is not a group of this status page"
+		}
+	}
+
+	for i, monitorID := range component.MonitorIDs {
+		if !slices.Contains(statusPage.MonitorIDs, monitorID) {
+			fields[fmt.Sprintf("monitors[%d]", i)] = "is not shown on this status page"
+		}
+	}
+
+	return fields, nil
+}
+
+func (app *application) componentGroupContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusPage := getStatusPageFromContext(r)
+
+		id := chi.URLParam(r, "groupId")
+		if id == "" {
+			app.badRequestError(w, r, errors.New("missing groupId parameter"))
+			return
+		}
+
+		ctx := r.Context()
+
+		group, err := app.store.ComponentGroups.GetByID(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		if group.StatusPageID != statusPage.ID {
+			app.notFoundError(w, r, store.ErrNotFound)
+			return
+		}
+
+		ctx = context.WithValue(ctx, componentGroupCtx, group)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getComponentGroupFromContext(r *http.Request) *store.ComponentGroup {
+	group, _ := r.Context().Value(componentGroupCtx).(*store.ComponentGroup)
+	return group
+}
+
+func (app *application) componentContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusPage := getStatusPageFromContext(r)
+
+		id := chi.URLParam(r, "componentId")
+		if id == "" {
+			app.badRequestError(w, r, errors.New("missing componentId parameter"))
+			return
+		}
+
+		ctx := r.Context()
+
+		component, err := app.store.Components.GetByID(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		if component.StatusPageID != statusPage.ID {
+			app.notFoundError(w, r, store.ErrNotFound)
+			return
+		}
+
+		ctx = context.WithValue(ctx, componentCtx, component)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getComponentFromContext(r *http.Request) *store.Component {
+	component, _ := r.Context().Value(componentCtx).(*store.Component)
+	return component
+}
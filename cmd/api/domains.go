@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/domains"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// customDomainPaths are the paths of a public status page, relative to
+// /status/{slug}, that are also served at the root of its custom domain.
+var customDomainPaths = map[string]bool{
+	"/":           true,
+	"/login":      true,
+	"/subscribe":  true,
+	"/feed.atom":  true,
+	"/feed.rss":   true,
+	"/badge.svg":  true,
+	"/badge.json": true,
+}
+
+// customDomainMiddleware serves the status page that has verified the
+// hostname of the request at the root of that hostname, by routing its
+// requests as if they were made to /status/{slug}. Requests for other
+// hostnames fall through to the usual routes.
+func (app *application) customDomainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostname := requestHostname(r)
+		if hostname == "" || hostname == "localhost" || hostname == app.publicHostname() || net.ParseIP(hostname) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !customDomainPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		statusPage, err := app.store.StatusPages.GetByHostname(r.Context(), hostname)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				next.ServeHTTP(w, r)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		path := "/status/" + statusPage.Slug
+		if r.URL.Path != "/" {
+			path += r.URL.Path
+		}
+
+		r.URL.Path = path
+		r.URL.RawPath = ""
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusPagePath returns the path of statusPage on the host the request was
+// made to.
+func statusPagePath(r *http.Request, statusPage *store.StatusPage) string {
+	if statusPage.HostnameVerified() && requestHostname(r) == statusPage.Hostname {
+		return "/"
+	}
+
+	return "/status/" + statusPage.Slug
+}
+
+// requestHostname returns the lowercased hostname of the request, without a
+// port.
+func requestHostname(r *http.Request) string {
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// publicHostname returns the hostname the server is reached on, which status
+// pages cannot claim.
+func (app *application) publicHostname() string {
+	publicURL, err := url.Parse(app.config.publicURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(publicURL.Hostname())
+}
+
+// hostnameTokenLength is the length of the tokens verifying hostnames.
+const hostnameTokenLength = 32
+
+// ensureHostnameToken gives status pages with a hostname the token that
+// verifies it, keeping any token they already have.
+func ensureHostnameToken(statusPage *store.StatusPage) error {
+	if statusPage.Hostname == "" || statusPage.HostnameToken != "" {
+		return nil
+	}
+
+	token, err := gonanoid.New(hostnameTokenLength)
+	if err != nil {
+		return err
+	}
+
+	statusPage.HostnameToken = token
+	return nil
+}
+
+type domainView struct {
+	Hostname   string          `json:"hostname"`
+	Verified   bool            `json:"verified"`
+	VerifiedAt *time.Time      `json:"verified_at"`
+	Record     *domains.Record `json:"record"`
+}
+
+func (app *application) newDomainView(statusPage *store.StatusPage) domainView {
+	view := domainView{
+		Hostname:   statusPage.Hostname,
+		Verified:   statusPage.HostnameVerified(),
+		VerifiedAt: statusPage.HostnameVerifiedAt,
+	}
+	if statusPage.Hostname != "" {
+		record := app.domains.Record(statusPage)
+		view.Record = &record
+	}
+
+	return view
+}
+
+// GetStatusPageDomain godoc
+//
+//	@Summary		Get Status Page Domain
+//	@Description	Get the custom domain of a status page and the TXT record that verifies it
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{object}	main.domainView
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/domain [get]
+func (app *application) getStatusPageDomainHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	if err := app.jsonResponse(w, http.StatusOK, app.newDomainView(statusPage)); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// VerifyStatusPageDomain godoc
+//
+//	@Summary		Verify Status Page Domain
+//	@Description	Look up the TXT record of the custom domain of a status page, and serve the page on the domain once it is found
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{object}	main.domainView
+//	@Failure		400	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		409	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/domain/verify [post]
+func (app *application) verifyStatusPageDomainHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+	ctx := r.Context()
+
+	if err := app.domains.Verify(ctx, statusPage); err != nil {
+		switch {
+		case errors.Is(err, domains.ErrNoHostname):
+			app.badRequestError(w, r, err)
+		case errors.Is(err, domains.ErrNotVerified):
+			record := app.domains.Record(statusPage)
+			app.failedValidationError(w, r, err, map[string]string{
+				"hostname": "create a TXT record named " + record.Name + " with the value " + record.Value,
+			})
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if !statusPage.HostnameVerified() {
+		now := time.Now().UTC().Truncate(time.Second)
+		statusPage.HostnameVerifiedAt = &now
+
+		if err := app.store.StatusPages.Update(ctx, statusPage); err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			case errors.Is(err, store.ErrDuplicateHostname):
+				app.conflictError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, app.newDomainView(statusPage)); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (app *application) internalServerError(w http.ResponseWriter, r *http.Request, err error) {
@@ -16,6 +18,12 @@ func (app *application) badRequestError(w http.ResponseWriter, r *http.Request,
 	writeJSONError(w, http.StatusBadRequest, err.Error())
 }
 
+func (app *application) unauthorizedError(w http.ResponseWriter, r *http.Request, err error) {
+	app.logger.Warnw("Unauthorized", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+
+	writeJSONError(w, http.StatusUnauthorized, "You are not allowed to access this resource.")
+}
+
 func (app *application) notFoundError(w http.ResponseWriter, r *http.Request, err error) {
 	app.logger.Warnf("Not Found", "method", r.Method, "path", r.URL.Path, "error", err.Error())
 
@@ -27,3 +35,16 @@ func (app *application) conflictError(w http.ResponseWriter, r *http.Request, er
 
 	writeJSONError(w, http.StatusConflict, err.Error())
 }
+
+func (app *application) rateLimitExceededError(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	app.logger.Warnw("Rate Limit Exceeded", "method", r.Method, "path", r.URL.Path, "retry_after", retryAfter.String())
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	writeJSONError(w, http.StatusTooManyRequests, "Too many requests, please try again later.")
+}
+
+func (app *application) failedValidationError(w http.ResponseWriter, r *http.Request, err error, fields map[string]string) {
+	app.logger.Warnw("Failed Validation", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+
+	writeJSONFieldErrors(w, http.StatusBadRequest, "One or more fields are invalid.", fields)
+}
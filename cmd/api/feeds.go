@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/statuspage"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// feedIDPrefix turns the IDs of status pages and feed entries into URNs,
+// which stay the same when a page changes its slug or the server its address.
+const feedIDPrefix = "urn:uptime-ume:"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	Link      atomLink     `xml:"link"`
+	Category  atomCategory `xml:"category"`
+	Content   atomContent  `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Updated     string  `xml:"atom:updated"`
+	Category    string  `xml:"category"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// atomFeedHandler serves the Atom feed of a public status page.
+func (app *application) atomFeedHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	now := time.Now()
+
+	entries, err := app.statusPages.Feed(r.Context(), statusPage, now)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	pageURL := app.notifier.StatusPageURL(statusPage)
+
+	feed := atomFeed{
+		Title:   statusPage.Name + " status",
+		ID:      feedIDPrefix + "status-page:" + statusPage.ID,
+		Updated: feedUpdated(entries, now).Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "alternate", Type: "text/html", Href: pageURL},
+			{Rel: "self", Type: "application/atom+xml", Href: pageURL + "/feed.atom"},
+		},
+		Entries: make([]atomEntry, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:     feedTitle(entry),
+			ID:        feedIDPrefix + entry.ID,
+			Published: entry.Published.UTC().Format(time.RFC3339),
+			Updated:   entry.Updated.UTC().Format(time.RFC3339),
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: pageURL},
+			Category:  atomCategory{Term: entry.Kind},
+			Content:   atomContent{Type: "html", Body: feedContent(entry)},
+		})
+	}
+
+	app.writeFeed(w, r, "application/atom+xml", feed)
+}
+
+// rssFeedHandler serves the RSS feed of a public status page. Items carry
+// the time of their latest update in atom:updated, as RSS has no element
+// for it.
+func (app *application) rssFeedHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	now := time.Now()
+
+	entries, err := app.statusPages.Feed(r.Context(), statusPage, now)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	pageURL := app.notifier.StatusPageURL(statusPage)
+
+	feed := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         statusPage.Name + " status",
+			Link:          pageURL,
+			Description:   "Incidents, state changes and maintenance of " + statusPage.Name,
+			LastBuildDate: feedUpdated(entries, now).Format(time.RFC1123Z),
+			Self:          atomLink{Rel: "self", Type: "application/rss+xml", Href: pageURL + "/feed.rss"},
+			Items:         make([]rssItem, 0, len(entries)),
+		},
+	}
+
+	for _, entry := range entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       feedTitle(entry),
+			Link:        pageURL,
+			GUID:        rssGUID{Value: feedIDPrefix + entry.ID},
+			PubDate:     entry.Published.UTC().Format(time.RFC1123Z),
+			Updated:     entry.Updated.UTC().Format(time.RFC3339),
+			Category:    entry.Kind,
+			Description: feedContent(entry),
+		})
+	}
+
+	app.writeFeed(w, r, "application/rss+xml", feed)
+}
+
+func (app *application) writeFeed(w http.ResponseWriter, r *http.Request, contentType string, feed any) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	buf.WriteTo(w)
+}
+
+// feedUpdated returns when the most recently updated entry changed, or now
+// for an empty feed.
+func feedUpdated(entries []statuspage.FeedEntry, now time.Time) time.Time {
+	if len(entries) == 0 {
+		return now.UTC().Truncate(time.Second)
+	}
+
+	return entries[0].Updated.UTC()
+}
+
+func feedTitle(entry statuspage.FeedEntry) string {
+	switch entry.Kind {
+	case store.PostIncident:
+		return "Incident: " + entry.Title
+	case store.PostMaintenance:
+		return "Maintenance: " + entry.Title
+	default:
+		return entry.Title
+	}
+}
+
+// feedContent renders the timeline of entry as HTML, newest update first.
+func feedContent(entry statuspage.FeedEntry) string {
+	var b strings.Builder
+
+	for _, update := range entry.Updates {
+		fmt.Fprintf(&b, "<p><strong>%s</strong> &middot; %s<br>%s</p>",
+			html.EscapeString(strings.ReplaceAll(update.Status, "_", " ")),
+			update.CreatedAt.UTC().Format("Jan 2, 2006 15:04 UTC"),
+			strings.ReplaceAll(html.EscapeString(update.Message), "\n", "<br>"),
+		)
+	}
+
+	return b.String()
+}
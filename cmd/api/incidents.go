@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// ListMonitorIncidents godoc
+//
+//	@Summary		List Monitor Incidents
+//	@Description	List the incidents of a monitor, most recent first
+//	@Tags			incidents
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Monitor ID"
+//	@Param			status	query		string	false	"open or resolved"
+//	@Param			from	query		string	false	"Only incidents ongoing at or after this RFC 3339 time"
+//	@Param			to		query		string	false	"Only incidents started before this RFC 3339 time"
+//	@Param			limit	query		int		false	"Maximum number of incidents, 50 by default"
+//	@Success		200		{array}		store.Incident
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/monitors/{id}/incidents [get]
+func (app *application) listMonitorIncidentsHandler(w http.ResponseWriter, r *http.Request) {
+	monitor := getMonitorFromContext(r)
+
+	filter, err := parseIncidentFilter(r)
+	if err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+	filter.MonitorID = monitor.ID
+
+	app.listIncidents(w, r, filter)
+}
+
+// ListIncidents godoc
+//
+//	@Summary		List Incidents
+//	@Description	List the incidents of all monitors, most recent first
+//	@Tags			incidents
+//	@Accept			json
+//	@Produce		json
+//	@Param			monitor_id	query		string	false	"Only incidents of this monitor"
+//	@Param			status		query		string	false	"open or resolved"
+//	@Param			from		query		string	false	"Only incidents ongoing at or after this RFC 3339 time"
+//	@Param			to			query		string	false	"Only incidents started before this RFC 3339 time"
+//	@Param			limit		query		int		false	"Maximum number of incidents, 50 by default"
+//	@Success		200			{array}		store.Incident
+//	@Failure		400			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		Bearer
+//	@Router			/incidents [get]
+func (app *application) listIncidentsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseIncidentFilter(r)
+	if err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+	filter.MonitorID = r.URL.Query().Get("monitor_id")
+
+	app.listIncidents(w, r, filter)
+}
+
+func (app *application) listIncidents(w http.ResponseWriter, r *http.Request, filter store.IncidentFilter) {
+	incidents, err := app.store.Incidents.List(r.Context(), filter)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, incidents); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func parseIncidentFilter(r *http.Request) (store.IncidentFilter, error) {
+	query := r.URL.Query()
+
+	filter := store.IncidentFilter{
+		Status: query.Get("status"),
+	}
+
+	if filter.Status != "" && filter.Status != store.IncidentOpen && filter.Status != store.IncidentResolved {
+		return filter, fmt.Errorf("status must be %s or %s", store.IncidentOpen, store.IncidentResolved)
+	}
+
+	var err error
+
+	if filter.From, filter.To, err = parseTimeRange(r); err != nil {
+		return filter, err
+	}
+
+	if filter.Limit, err = parseLimit(r); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseTimeRange reads the optional RFC 3339 from and to query parameters.
+func parseTimeRange(r *http.Request) (from, to time.Time, err error) {
+	query := r.URL.Query()
+
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			return from, to, fmt.Errorf("from must be an RFC 3339 time")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			return from, to, fmt.Errorf("to must be an RFC 3339 time")
+		}
+	}
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
+}
+
+func parseLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultListLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxListLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+	}
+
+	return limit, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"regexp"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -11,8 +12,15 @@ const MAX_BYTES = 1_048_576 // 1MB
 
 var Validate *validator.Validate
 
+// slugPattern matches lowercase words joined by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 func init() {
 	Validate = validator.New(validator.WithRequiredStructEnabled())
+
+	Validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
 }
 
 func writeJSON(w http.ResponseWriter, status int, data any) error {
@@ -38,6 +46,15 @@ func writeJSONError(w http.ResponseWriter, status int, message string) error {
 	return writeJSON(w, status, &envelope{Error: message})
 }
 
+func writeJSONFieldErrors(w http.ResponseWriter, status int, message string, fields map[string]string) error {
+	type envelope struct {
+		Error  string            `json:"error"`
+		Fields map[string]string `json:"fields"`
+	}
+
+	return writeJSON(w, status, &envelope{Error: message, Fields: fields})
+}
+
 func (app *application) jsonResponse(w http.ResponseWriter, status int, data any) error {
 	type envelope struct {
 		Data any `json:"data"`
@@ -1,8 +1,26 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/changefeed"
+	"github.com/marekh19/uptime-ume/internal/checker"
 	"github.com/marekh19/uptime-ume/internal/db"
+	"github.com/marekh19/uptime-ume/internal/domains"
 	"github.com/marekh19/uptime-ume/internal/env"
+	"github.com/marekh19/uptime-ume/internal/incidents"
+	"github.com/marekh19/uptime-ume/internal/notify"
+	"github.com/marekh19/uptime-ume/internal/ratelimit"
+	"github.com/marekh19/uptime-ume/internal/results"
+	"github.com/marekh19/uptime-ume/internal/retention"
+	"github.com/marekh19/uptime-ume/internal/rollup"
+	"github.com/marekh19/uptime-ume/internal/scheduler"
+	"github.com/marekh19/uptime-ume/internal/signer"
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/statuspage"
 	"github.com/marekh19/uptime-ume/internal/store"
 	"go.uber.org/zap"
 )
@@ -10,6 +28,9 @@ import (
 const (
 	version = "0.0.1"
 	apiBase = "/api/v1"
+
+	// shutdownTimeout bounds each step of a graceful shutdown.
+	shutdownTimeout = 10 * time.Second
 )
 
 //	@title			Uptime Ume API
@@ -32,15 +53,38 @@ func main() {
 	env.Load()
 
 	cfg := config{
-		addr:   env.GetString("ADDR", ":8080"),
-		apiURL: env.GetString("EXTERNAL_URL", "localhost:8080"),
+		addr:      env.GetString("ADDR", ":8080"),
+		adminAddr: env.GetString("ADMIN_ADDR", "localhost:6060"),
+		apiURL:    env.GetString("EXTERNAL_URL", "localhost:8080"),
 		db: dbConfig{
 			addr:         env.GetString("DB_URL", "file:database.db"),
 			maxOpenConns: env.GetInt("DB_MAX_OPEN_CONNS", 10),
 			maxIdleConns: env.GetInt("DB_MAX_IDLE_CONNS", 5),
 			maxIdleTime:  env.GetString("DB_MAX_IDLE_TIME", "15m"),
 		},
-		env: env.GetString("ENV", "development"),
+		env:                env.GetString("ENV", "development"),
+		changefeedCapacity: env.GetInt("CHANGEFEED_CAPACITY", 1024),
+		results: results.BatchConfig{
+			Size:     env.GetInt("RESULTS_BATCH_SIZE", 100),
+			Interval: time.Duration(env.GetInt("RESULTS_FLUSH_INTERVAL_MS", 500)) * time.Millisecond,
+			Capacity: env.GetInt("RESULTS_BUFFER_CAPACITY", 10000),
+		},
+		retention: retention.Policy{
+			RawDays:    env.GetInt("RETENTION_RAW_DAYS", 14),
+			MinuteDays: env.GetInt("RETENTION_MINUTE_DAYS", 30),
+			HourDays:   env.GetInt("RETENTION_HOUR_DAYS", 365),
+			DayDays:    env.GetInt("RETENTION_DAY_DAYS", 0),
+		},
+		publicURL:         env.GetString("PUBLIC_URL", "http://localhost:8080"),
+		secretKey:         env.GetString("SECRET_KEY", ""),
+		trustProxyHeaders: env.GetString("TRUST_PROXY_HEADERS", "false") == "true",
+		dnsResolver:       env.GetString("DNS_RESOLVER", ""),
+		smtp: notify.SMTPConfig{
+			Addr:     env.GetString("SMTP_ADDR", "localhost:1025"),
+			From:     env.GetString("SMTP_FROM", "Uptime Ume <status@localhost>"),
+			Username: env.GetString("SMTP_USERNAME", ""),
+			Password: env.GetString("SMTP_PASSWORD", ""),
+		},
 	}
 
 	// Logger
@@ -63,13 +107,113 @@ func main() {
 
 	store := store.NewStorage(db)
 
+	// Monitor kinds
+	push := checker.NewPushChecker()
+	kinds := checker.NewRegistry(
+		checker.NewHTTPChecker(),
+		checker.NewTCPChecker(),
+		checker.NewDNSChecker(),
+		checker.NewTLSChecker(),
+		push,
+	)
+
+	// Monitor change feed
+	feed := changefeed.New(cfg.changefeedCapacity)
+
+	// Signing of links and cookies
+	secretKey := []byte(cfg.secretKey)
+	if len(secretKey) == 0 {
+		secretKey = make([]byte, 32)
+		if _, err := rand.Read(secretKey); err != nil {
+			logger.Panic(err.Error())
+		}
+		logger.Warn("SECRET_KEY is not set, signed links and cookies will stop working on restart")
+	}
+	signer := signer.New(secretKey)
+
+	// Status page subscriptions
+	notifier := notify.NewDispatcher(store, notify.NewSMTPMailer(cfg.smtp), signer, cfg.publicURL, logger)
+
+	// Check results and incidents
+	tracker := incidents.NewTracker(store, feed, notifier)
+	recorder := results.NewRecorder(store, tracker, cfg.results, logger)
+
+	// Background workers, stopped once the server has shut down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var workers sync.WaitGroup
+	startWorker := func(name string, run func(context.Context) error) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if err := run(ctx); err != nil {
+				logger.Errorw(name+" has stopped", "error", err.Error())
+			}
+		}()
+	}
+
+	// Scheduler
+	scheduler := scheduler.New(store, kinds, feed, recorder, logger)
+	startWorker("Scheduler", scheduler.Run)
+
+	// Incidents
+	startWorker("Incident tracker", tracker.Run)
+
+	// Ping result rollups
+	startWorker("Rollup job", rollup.New(store, cfg.results.Interval, logger).Run)
+
+	// Retention
+	startWorker("Pruner", retention.NewPruner(store, cfg.retention, logger).Run)
+
+	// Notifications
+	startWorker("Notifier", notifier.Run)
+
+	// Public status pages
+	templates, err := parseTemplates()
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
+	calculator := stats.NewCalculator(store)
+
 	app := &application{
-		config: cfg,
-		store:  store,
-		logger: logger,
+		config:      cfg,
+		store:       store,
+		kinds:       kinds,
+		feed:        feed,
+		push:        push,
+		scheduler:   scheduler,
+		stats:       calculator,
+		statusPages: statuspage.NewBuilder(store, calculator),
+		notifier:    notifier,
+		signer:      signer,
+		domains:     domains.NewVerifier(cfg.dnsResolver),
+		templates:   templates,
+		logger:      logger,
+
+		loginsPerIP:   ratelimit.New(loginBurstPerIP, loginIntervalPerIP),
+		loginsPerPage: ratelimit.New(loginBurstPerPage, loginIntervalPerPage),
+
+		subscriptionsPerIP: ratelimit.New(subscribeBurstPerIP, subscribeIntervalPerIP),
 	}
 
 	mux := app.mount()
 
-	logger.Fatal(app.run(mux))
+	err = app.run(mux)
+
+	// Stop producing results before writing out the buffered ones.
+	cancel()
+	workers.Wait()
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer flushCancel()
+
+	if err := recorder.Close(flushCtx); err != nil {
+		logger.Errorw("Failed to store buffered ping results", "error", err.Error())
+	}
+
+	if err != nil {
+		logger.Fatal(err)
+	}
 }
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/changefeed"
+	"github.com/marekh19/uptime-ume/internal/checker"
 	"github.com/marekh19/uptime-ume/internal/store"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 )
@@ -15,12 +18,16 @@ type monitorKey string
 const monitorCtx monitorKey = "monitor"
 
 type CreateMonitorPayload struct {
-	Name     string `json:"name" validate:"required,max=100"`
-	Address  string `json:"address" validate:"required,url"`
-	Method   string `json:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
-	Kind     string `json:"kind"`
-	Config   string `json:"config"`
-	Interval int    `json:"interval" validate:"required,gt=0"`
+	Name          string           `json:"name" validate:"required,max=100"`
+	Address       string           `json:"address" validate:"max=2048"`
+	Method        string           `json:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
+	Kind          string           `json:"kind"`
+	Config        json.RawMessage  `json:"config" swaggertype:"object"`
+	Interval      int              `json:"interval" validate:"required,gt=0"`
+	Retries       int              `json:"retries" validate:"gte=0,lte=10"`
+	RetryInterval int              `json:"retry_interval" validate:"gte=0"`
+	Retention     *store.Retention `json:"retention"`
+	BadgeEnabled  bool             `json:"badge_enabled"`
 }
 
 // CreateMonitor godoc
@@ -48,6 +55,16 @@ func (app *application) createMonitorHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	kind := payload.Kind
+	if kind == "" {
+		kind = checker.DefaultKind
+	}
+
+	config := payload.Config
+	if len(config) == 0 || string(config) == "null" {
+		config = json.RawMessage("{}")
+	}
+
 	id, err := gonanoid.New()
 	if err != nil {
 		app.internalServerError(w, r, err)
@@ -58,14 +75,28 @@ func (app *application) createMonitorHandler(w http.ResponseWriter, r *http.Requ
 	userId := "1"
 
 	monitor := &store.Monitor{
-		ID:       id,
-		UserId:   userId,
-		Name:     payload.Name,
-		Address:  payload.Address,
-		Interval: payload.Interval,
-		Method:   payload.Method,
-		Kind:     payload.Kind,
-		Config:   payload.Config,
+		ID:            id,
+		UserId:        userId,
+		Name:          payload.Name,
+		Address:       payload.Address,
+		Interval:      payload.Interval,
+		Method:        payload.Method,
+		Kind:          kind,
+		Config:        config,
+		Retries:       payload.Retries,
+		RetryInterval: payload.RetryInterval,
+		Retention:     payload.Retention,
+		BadgeEnabled:  payload.BadgeEnabled,
+	}
+
+	if err := app.validateMonitor(monitor); err != nil {
+		app.monitorValidationError(w, r, err)
+		return
+	}
+
+	if err := ensurePushToken(monitor); err != nil {
+		app.internalServerError(w, r, err)
+		return
 	}
 
 	ctx := r.Context()
@@ -75,6 +106,8 @@ func (app *application) createMonitorHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	app.feed.Publish(changefeed.MonitorCreated, monitor)
+
 	if err := app.jsonResponse(w, http.StatusCreated, monitor); err != nil {
 		app.internalServerError(w, r, err)
 		return
@@ -143,15 +176,11 @@ func (app *application) listMonitorsHandler(w http.ResponseWriter, r *http.Reque
 //	@Security		Bearer
 //	@Router			/monitors/{id} [delete]
 func (app *application) deleteMonitorHandler(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		app.badRequestError(w, r, errors.New("missing id parameter"))
-		return
-	}
+	monitor := getMonitorFromContext(r)
 
 	ctx := r.Context()
 
-	if err := app.store.Monitors.Delete(ctx, id); err != nil {
+	if err := app.store.Monitors.Delete(ctx, monitor.ID); err != nil {
 		switch {
 		case errors.Is(err, store.ErrNotFound):
 			app.notFoundError(w, r, err)
@@ -161,16 +190,23 @@ func (app *application) deleteMonitorHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	app.feed.Publish(changefeed.MonitorDeleted, monitor)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
 type UpdateMonitorPayload struct {
-	Name     *string `json:"name" validate:"omitempty,max=100"`
-	Address  *string `json:"address" validate:"omitempty,url"`
-	Method   *string `json:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
-	Kind     *string `json:"kind"`
-	Config   *string `json:"config"`
-	Interval *int    `json:"interval" validate:"omitempty,gt=0"`
+	Name          *string         `json:"name" validate:"omitempty,max=100"`
+	Address       *string         `json:"address" validate:"omitempty,max=2048"`
+	Method        *string         `json:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE HEAD OPTIONS"`
+	Kind          *string         `json:"kind"`
+	Config        json.RawMessage `json:"config" swaggertype:"object"`
+	Interval      *int            `json:"interval" validate:"omitempty,gt=0"`
+	Retries       *int            `json:"retries" validate:"omitempty,gte=0,lte=10"`
+	RetryInterval *int            `json:"retry_interval" validate:"omitempty,gte=0"`
+	// Retention replaces the retention override, and null clears it.
+	Retention    json.RawMessage `json:"retention" swaggertype:"object"`
+	BadgeEnabled *bool           `json:"badge_enabled"`
 }
 
 // UpdateMonitor godoc
@@ -219,13 +255,57 @@ func (app *application) updateMonitorHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	if payload.Config != nil {
-		monitor.Config = *payload.Config
+		monitor.Config = payload.Config
+		if string(monitor.Config) == "null" {
+			monitor.Config = json.RawMessage("{}")
+		}
 	}
 
 	if payload.Interval != nil {
 		monitor.Interval = *payload.Interval
 	}
 
+	if payload.Retries != nil {
+		monitor.Retries = *payload.Retries
+	}
+
+	if payload.RetryInterval != nil {
+		monitor.RetryInterval = *payload.RetryInterval
+	}
+
+	if payload.Retention != nil {
+		var retention *store.Retention
+		if err := json.Unmarshal(payload.Retention, &retention); err != nil {
+			app.badRequestError(w, r, err)
+			return
+		}
+
+		if retention != nil {
+			if err := Validate.Struct(retention); err != nil {
+				app.badRequestError(w, r, err)
+				return
+			}
+		}
+
+		monitor.Retention = retention
+	}
+
+	if payload.BadgeEnabled != nil {
+		monitor.BadgeEnabled = *payload.BadgeEnabled
+	}
+
+	if payload.Address != nil || payload.Kind != nil || payload.Config != nil {
+		if err := app.validateMonitor(monitor); err != nil {
+			app.monitorValidationError(w, r, err)
+			return
+		}
+	}
+
+	if err := ensurePushToken(monitor); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
 	if err := app.store.Monitors.Update(r.Context(), monitor); err != nil {
 		switch {
 		case errors.Is(err, store.ErrNotFound):
@@ -236,12 +316,34 @@ func (app *application) updateMonitorHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	app.feed.Publish(changefeed.MonitorUpdated, monitor)
+
 	if err := app.jsonResponse(w, http.StatusNoContent, monitor); err != nil {
 		app.internalServerError(w, r, err)
 		return
 	}
 }
 
+// validateMonitor checks the kind-specific parts of monitor: that its kind is
+// registered and that its address and config suit that kind.
+func (app *application) validateMonitor(monitor *store.Monitor) error {
+	if monitor.Kind == "" {
+		return &checker.ValidationError{Fields: map[string]string{"kind": "is required"}}
+	}
+
+	return app.kinds.Validate(monitor)
+}
+
+func (app *application) monitorValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *checker.ValidationError
+	if errors.As(err, &validationErr) {
+		app.failedValidationError(w, r, err, validationErr.Fields)
+		return
+	}
+
+	app.internalServerError(w, r, err)
+}
+
 func (app *application) monitorContextMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+var pingResultStatuses = []string{
+	checker.StatusUp,
+	checker.StatusDegraded,
+	checker.StatusDown,
+	checker.StatusPending,
+}
+
+// PingResultsPage is one page of a monitor's check history. NextCursor is
+// empty on the last page.
+type PingResultsPage struct {
+	Results    []*store.PingResult `json:"results"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// ListMonitorResults godoc
+//
+//	@Summary		List Monitor Results
+//	@Description	List the check results of a monitor, most recent first
+//	@Tags			monitors
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Monitor ID"
+//	@Param			status	query		string	false	"Comma-separated statuses: up, degraded, down, pending"
+//	@Param			from	query		string	false	"Only results at or after this RFC 3339 time"
+//	@Param			to		query		string	false	"Only results before this RFC 3339 time"
+//	@Param			limit	query		int		false	"Maximum number of results, 50 by default"
+//	@Param			cursor	query		string	false	"next_cursor of the previous page"
+//	@Success		200		{object}	main.PingResultsPage
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/monitors/{id}/results [get]
+func (app *application) listMonitorResultsHandler(w http.ResponseWriter, r *http.Request) {
+	monitor := getMonitorFromContext(r)
+
+	filter, err := parsePingResultFilter(r)
+	if err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+	filter.MonitorID = monitor.ID
+
+	// Fetch one extra result to learn whether another page follows.
+	limit := filter.Limit
+	filter.Limit++
+
+	results, err := app.store.PingResults.List(r.Context(), filter)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	page := PingResultsPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		last := page.Results[limit-1]
+		page.NextCursor = store.PingResultCursor{Timestamp: last.Timestamp, ID: last.ID}.Encode()
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, page); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func parsePingResultFilter(r *http.Request) (store.PingResultFilter, error) {
+	query := r.URL.Query()
+
+	var filter store.PingResultFilter
+
+	if value := query.Get("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			if !isPingResultStatus(status) {
+				return filter, fmt.Errorf("status must be one of %s", strings.Join(pingResultStatuses, ", "))
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	var err error
+
+	if filter.From, filter.To, err = parseTimeRange(r); err != nil {
+		return filter, err
+	}
+
+	if filter.Limit, err = parseLimit(r); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if filter.After, err = store.DecodePingResultCursor(value); err != nil {
+			return filter, errors.New("cursor is invalid")
+		}
+	}
+
+	return filter, nil
+}
+
+func isPingResultStatus(status string) bool {
+	for _, s := range pingResultStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
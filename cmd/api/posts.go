@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type postKey string
+
+const postCtx postKey = "post"
+
+// postStatuses lists the statuses a post of each kind may have, the first
+// being the one it starts with.
+var postStatuses = map[string][]string{
+	store.PostIncident:    {store.PostInvestigating, store.PostIdentified, store.PostMonitoring, store.PostResolved},
+	store.PostMaintenance: {store.PostScheduled, store.PostInProgress, store.PostCompleted},
+}
+
+type CreatePostPayload struct {
+	Kind       string     `json:"kind" validate:"required,oneof=incident maintenance"`
+	Title      string     `json:"title" validate:"required,max=200"`
+	Severity   string     `json:"severity" validate:"omitempty,oneof=minor major critical"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message" validate:"required,max=5000"`
+	MonitorIDs []string   `json:"monitors" validate:"unique,dive,required"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+// CreatePost godoc
+//
+//	@Summary		Create Post
+//	@Description	Announce an incident or a scheduled maintenance on a status page. The message starts the post's timeline.
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Status Page ID"
+//	@Param			payload	body		main.CreatePostPayload	true	"CreatePostPayload"
+//	@Success		201		{object}	store.Post
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts [post]
+func (app *application) createPostHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	var payload CreatePostPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	updateID, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	post := &store.Post{
+		ID:           id,
+		StatusPageID: statusPage.ID,
+		Kind:         payload.Kind,
+		Title:        payload.Title,
+		Severity:     payload.Severity,
+		Status:       payload.Status,
+		MonitorIDs:   payload.MonitorIDs,
+		StartsAt:     now,
+		EndsAt:       payload.EndsAt,
+	}
+	if post.MonitorIDs == nil {
+		post.MonitorIDs = []string{}
+	}
+	if post.Status == "" {
+		post.Status = postStatuses[post.Kind][0]
+	}
+	if payload.StartsAt != nil {
+		post.StartsAt = *payload.StartsAt
+	}
+	if post.Kind == store.PostIncident && post.Status == store.PostResolved && post.EndsAt == nil {
+		post.EndsAt = &now
+	}
+
+	if fields := validatePost(post, statusPage); len(fields) > 0 {
+		app.failedValidationError(w, r, errors.New("invalid post"), fields)
+		return
+	}
+
+	post.Updates = []*store.PostUpdate{{
+		ID:        updateID,
+		Status:    post.Status,
+		Message:   payload.Message,
+		CreatedAt: now,
+	}}
+
+	if err := app.store.Posts.Create(r.Context(), post); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	app.notifier.PostPublished(post)
+
+	if err := app.jsonResponse(w, http.StatusCreated, post); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListPosts godoc
+//
+//	@Summary		List Posts
+//	@Description	List the posts of a status page, latest starting first
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Status Page ID"
+//	@Param			kind	query		string	false	"incident or maintenance"
+//	@Param			limit	query		int		false	"Maximum number of posts, 50 by default"
+//	@Success		200		{array}		store.Post
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts [get]
+func (app *application) listPostsHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	filter := store.PostFilter{
+		StatusPageID: statusPage.ID,
+		Kind:         r.URL.Query().Get("kind"),
+	}
+
+	if _, ok := postStatuses[filter.Kind]; filter.Kind != "" && !ok {
+		app.badRequestError(w, r, fmt.Errorf("kind must be %s or %s", store.PostIncident, store.PostMaintenance))
+		return
+	}
+
+	var err error
+	if filter.Limit, err = parseLimit(r); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	posts, err := app.store.Posts.List(r.Context(), filter)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, posts); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetPost godoc
+//
+//	@Summary		Get Post
+//	@Description	Get a post of a status page with its timeline
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Status Page ID"
+//	@Param			postId	path		string	true	"Post ID"
+//	@Success		200		{object}	store.Post
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts/{postId} [get]
+func (app *application) getPostHandler(w http.ResponseWriter, r *http.Request) {
+	post := getPostFromContext(r)
+
+	if err := app.jsonResponse(w, http.StatusOK, post); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+type UpdatePostPayload struct {
+	Title      *string    `json:"title" validate:"omitempty,max=200"`
+	Severity   *string    `json:"severity" validate:"omitempty,oneof=minor major critical"`
+	MonitorIDs []string   `json:"monitors" validate:"omitempty,unique,dive,required"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+// UpdatePost godoc
+//
+//	@Summary		Update Post
+//	@Description	Correct the details of a post. Status changes are made by adding an update to its timeline.
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Status Page ID"
+//	@Param			postId	path		string					true	"Post ID"
+//	@Param			payload	body		main.UpdatePostPayload	true	"UpdatePostPayload"
+//	@Success		200		{object}	store.Post
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts/{postId} [patch]
+func (app *application) updatePostHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+	post := getPostFromContext(r)
+
+	var payload UpdatePostPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if payload.Title != nil {
+		post.Title = *payload.Title
+	}
+
+	if payload.Severity != nil {
+		post.Severity = *payload.Severity
+	}
+
+	if payload.MonitorIDs != nil {
+		post.MonitorIDs = payload.MonitorIDs
+	}
+
+	if payload.StartsAt != nil {
+		post.StartsAt = *payload.StartsAt
+	}
+
+	if payload.EndsAt != nil {
+		post.EndsAt = payload.EndsAt
+	}
+
+	if fields := validatePost(post, statusPage); len(fields) > 0 {
+		app.failedValidationError(w, r, errors.New("invalid post"), fields)
+		return
+	}
+
+	if err := app.store.Posts.Update(r.Context(), post); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, post); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+type CreatePostUpdatePayload struct {
+	Status  string `json:"status" validate:"required"`
+	Message string `json:"message" validate:"required,max=5000"`
+}
+
+// CreatePostUpdate godoc
+//
+//	@Summary		Create Post Update
+//	@Description	Add an update to the timeline of a post, moving it to the update's status. Resolving an incident or completing a maintenance ends it.
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Status Page ID"
+//	@Param			postId	path		string							true	"Post ID"
+//	@Param			payload	body		main.CreatePostUpdatePayload	true	"CreatePostUpdatePayload"
+//	@Success		201		{object}	store.Post
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts/{postId}/updates [post]
+func (app *application) createPostUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	post := getPostFromContext(r)
+
+	var payload CreatePostUpdatePayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if !slices.Contains(postStatuses[post.Kind], payload.Status) {
+		app.failedValidationError(w, r, errors.New("invalid post status"), map[string]string{
+			"status": fmt.Sprintf("must be one of %v", postStatuses[post.Kind]),
+		})
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	switch {
+	case payload.Status == store.PostResolved && post.Status != store.PostResolved:
+		post.EndsAt = &now
+	case post.Kind == store.PostIncident && payload.Status != store.PostResolved:
+		// A reopened incident is ongoing again.
+		post.EndsAt = nil
+	case payload.Status == store.PostCompleted && post.EndsAt != nil && now.Before(*post.EndsAt):
+		// Maintenance that finished early no longer covers the rest of
+		// its window.
+		endsAt := now
+		if endsAt.Before(post.StartsAt) {
+			endsAt = post.StartsAt
+		}
+		post.EndsAt = &endsAt
+	}
+	post.Status = payload.Status
+
+	update := &store.PostUpdate{
+		ID:        id,
+		Status:    payload.Status,
+		Message:   payload.Message,
+		CreatedAt: now,
+	}
+
+	if err := app.store.Posts.AddUpdate(r.Context(), post, update); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	post.Updates = append([]*store.PostUpdate{update}, post.Updates...)
+
+	app.notifier.PostUpdated(post, update)
+
+	if err := app.jsonResponse(w, http.StatusCreated, post); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// DeletePost godoc
+//
+//	@Summary		Delete Post
+//	@Description	Delete a post of a status page with its timeline
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path	string	true	"Status Page ID"
+//	@Param			postId	path	string	true	"Post ID"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/posts/{postId} [delete]
+func (app *application) deletePostHandler(w http.ResponseWriter, r *http.Request) {
+	post := getPostFromContext(r)
+
+	if err := app.store.Posts.Delete(r.Context(), post.ID); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validatePost checks the rules that depend on the kind of post, and that
+// its monitors are shown on the status page.
+func validatePost(post *store.Post, statusPage *store.StatusPage) map[string]string {
+	fields := make(map[string]string)
+
+	if !slices.Contains(postStatuses[post.Kind], post.Status) {
+		fields["status"] = fmt.Sprintf("must be one of %v", postStatuses[post.Kind])
+	}
+
+	switch post.Kind {
+	case store.PostIncident:
+		if post.Severity == "" {
+			fields["severity"] = "is required for incidents"
+		}
+		if post.EndsAt != nil && post.Status != store.PostResolved {
+			fields["ends_at"] = "can only be set on resolved incidents"
+		}
+	case store.PostMaintenance:
+		if post.Severity != "" {
+			fields["severity"] = "cannot be set on maintenance"
+		}
+		if post.EndsAt == nil {
+			fields["ends_at"] = "is required for maintenance"
+		}
+	}
+
+	if post.EndsAt != nil && post.EndsAt.Before(post.StartsAt) {
+		fields["ends_at"] = "cannot be before starts_at"
+	}
+
+	for i, monitorID := range post.MonitorIDs {
+		if !slices.Contains(statusPage.MonitorIDs, monitorID) {
+			fields[fmt.Sprintf("monitors[%d]", i)] = "is not shown on this status page"
+		}
+	}
+
+	return fields
+}
+
+func (app *application) postContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusPage := getStatusPageFromContext(r)
+
+		id := chi.URLParam(r, "postId")
+		if id == "" {
+			app.badRequestError(w, r, errors.New("missing postId parameter"))
+			return
+		}
+
+		ctx := r.Context()
+
+		post, err := app.store.Posts.GetByID(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		if post.StatusPageID != statusPage.ID {
+			app.notFoundError(w, r, store.ErrNotFound)
+			return
+		}
+
+		ctx = context.WithValue(ctx, postCtx, post)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getPostFromContext(r *http.Request) *store.Post {
+	post, _ := r.Context().Value(postCtx).(*store.Post)
+	return post
+}
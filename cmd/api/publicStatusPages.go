@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// PublicStatusPage godoc
+//
+//	@Summary		Get Public Status Page
+//	@Description	Get what visitors of a status page see: the current state and 90-day uptime of its monitors, and recent incidents
+//	@Tags			public
+//	@Produce		json
+//	@Param			slug	path		string	true	"Status page slug"
+//	@Success		200		{object}	statuspage.View
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Router			/public/status-pages/{slug} [get]
+func (app *application) getPublicStatusPageHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	view, err := app.statusPages.Build(r.Context(), statusPage, time.Now())
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, view); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// statusPageHTMLHandler renders the public status page.
+func (app *application) statusPageHTMLHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	view, err := app.statusPages.Build(r.Context(), statusPage, time.Now())
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	app.render(w, r, http.StatusOK, "status.html", view)
+}
+
+// publicStatusPageMiddleware loads the status page named by the slug URL
+// parameter. Missing pages get an HTML or JSON not found response,
+// depending on html.
+func (app *application) publicStatusPageMiddleware(html bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			slug := chi.URLParam(r, "slug")
+
+			ctx := r.Context()
+
+			statusPage, err := app.store.StatusPages.GetBySlug(ctx, slug)
+			if err != nil {
+				switch {
+				case errors.Is(err, store.ErrNotFound) && html:
+					app.render(w, r, http.StatusNotFound, "not_found.html", nil)
+				case errors.Is(err, store.ErrNotFound):
+					app.notFoundError(w, r, err)
+				default:
+					app.internalServerError(w, r, err)
+				}
+				return
+			}
+
+			ctx = context.WithValue(ctx, statusPageCtx, statusPage)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
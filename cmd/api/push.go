@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const pushTokenLength = 32
+
+type PushPayload struct {
+	Status  string `validate:"oneof=up down"`
+	Message string `validate:"max=1000"`
+}
+
+// Push godoc
+//
+//	@Summary		Push Heartbeat
+//	@Description	Record a heartbeat for a push monitor. Parameters may be sent in the query string or as a form body.
+//	@Tags			push
+//	@Produce		json
+//	@Param			token	path		string	true	"Push token"
+//	@Param			status	query		string	false	"Reported status, up (default) or down"
+//	@Param			msg		query		string	false	"Message to store with the heartbeat"
+//	@Success		202		{object}	store.PingResult
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Router			/push/{token} [get]
+//	@Router			/push/{token} [post]
+func (app *application) pushHandler(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	payload := PushPayload{
+		Status:  r.FormValue("status"),
+		Message: r.FormValue("msg"),
+	}
+	if payload.Status == "" {
+		payload.Status = checker.StatusUp
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	monitor, err := app.store.Monitors.GetByPushToken(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if monitor.Kind != checker.KindPush {
+		app.notFoundError(w, r, errors.New("monitor does not accept heartbeats"))
+		return
+	}
+
+	pingResult := &store.PingResult{
+		MonitorID: monitor.ID,
+		Status:    payload.Status,
+		Timestamp: time.Now(),
+		Message:   payload.Message,
+	}
+
+	if err := app.scheduler.Report(ctx, monitor, pingResult); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	app.push.Heartbeat(monitor.ID, pingResult.Timestamp)
+
+	if err := app.jsonResponse(w, http.StatusAccepted, pingResult); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// ensurePushToken gives push monitors the secret they receive heartbeats
+// through, keeping any token they already have.
+func ensurePushToken(monitor *store.Monitor) error {
+	if monitor.Kind != checker.KindPush || monitor.PushToken != "" {
+		return nil
+	}
+
+	token, err := gonanoid.New(pushTokenLength)
+	if err != nil {
+		return err
+	}
+
+	monitor.PushToken = token
+	return nil
+}
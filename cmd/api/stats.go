@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/stats"
+)
+
+// GetMonitorStats godoc
+//
+//	@Summary		Get Monitor Stats
+//	@Description	Get uptime, downtime, incident count and response time percentiles of a monitor. Without parameters the 24h, 7d and 30d windows are returned.
+//	@Tags			monitors
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Monitor ID"
+//	@Param			window	query		string	false	"Only this standard window: 24h, 7d or 30d"
+//	@Param			from	query		string	false	"Start of a custom window as an RFC 3339 time"
+//	@Param			to		query		string	false	"End of a custom window as an RFC 3339 time, now by default"
+//	@Success		200		{array}		stats.Stats
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/monitors/{id}/stats [get]
+func (app *application) getMonitorStatsHandler(w http.ResponseWriter, r *http.Request) {
+	monitor := getMonitorFromContext(r)
+
+	query := r.URL.Query()
+
+	from, to, err := parseTimeRange(r)
+	if err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	windowName := query.Get("window")
+	custom := !from.IsZero() || !to.IsZero()
+
+	if custom && windowName != "" {
+		app.badRequestError(w, r, errors.New("window cannot be combined with from and to"))
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+
+	if custom {
+		if from.IsZero() {
+			app.badRequestError(w, r, errors.New("from is required for a custom window"))
+			return
+		}
+		if to.IsZero() {
+			to = now
+		}
+
+		result, err := app.stats.Range(ctx, monitor.ID, from, to)
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+
+		if err := app.jsonResponse(w, http.StatusOK, []*stats.Stats{result}); err != nil {
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	windows := stats.StandardWindows
+	if windowName != "" {
+		window, ok := stats.LookupWindow(windowName)
+		if !ok {
+			app.badRequestError(w, r, errors.New("window must be 24h, 7d or 30d"))
+			return
+		}
+		windows = []stats.Window{window}
+	}
+
+	results := make([]*stats.Stats, 0, len(windows))
+	for _, window := range windows {
+		result, err := app.stats.Window(ctx, monitor.ID, window, now)
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		results = append(results, result)
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, results); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// statusPageAccessDuration is how long visitors stay logged in to a
+// password-protected status page.
+const statusPageAccessDuration = 30 * 24 * time.Hour
+
+// Password guesses on status pages are throttled per client and per page,
+// each allowing a burst of attempts and then one attempt per interval.
+const (
+	loginBurstPerIP      = 10
+	loginIntervalPerIP   = time.Minute
+	loginBurstPerPage    = 30
+	loginIntervalPerPage = 10 * time.Second
+)
+
+// loginView is the data of login.html, shown instead of protected status
+// pages.
+type loginView struct {
+	Name string
+	Slug string
+	// PasswordProtected is false for pages only shown to allowed IPs, which
+	// visitors cannot log in to.
+	PasswordProtected bool
+	Error             string
+}
+
+// statusPageAccessMiddleware hides protected status pages from visitors who
+// neither come from an allowed network nor have logged in with the page's
+// password. They get the login form or, unless html, an unauthorized
+// response.
+func (app *application) statusPageAccessMiddleware(html bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			statusPage := getStatusPageFromContext(r)
+
+			if app.canViewStatusPage(r, statusPage) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !html {
+				app.unauthorizedError(w, r, errors.New("status page is protected"))
+				return
+			}
+
+			app.render(w, r, http.StatusUnauthorized, "login.html", loginView{
+				Name:              statusPage.Name,
+				Slug:              statusPage.Slug,
+				PasswordProtected: statusPage.Password.IsSet(),
+			})
+		})
+	}
+}
+
+func (app *application) canViewStatusPage(r *http.Request, statusPage *store.StatusPage) bool {
+	if !statusPage.Protected() {
+		return true
+	}
+
+	if ipAllowed(app.clientAddr(r), statusPage.AllowedIPs) {
+		return true
+	}
+
+	return statusPage.Password.IsSet() && app.validAccessCookie(r, statusPage)
+}
+
+type peerAddrKey string
+
+const peerAddrCtx peerAddrKey = "peerAddr"
+
+// peerAddrMiddleware remembers the address of the connected peer before the
+// RealIP middleware replaces it with the one in the proxy headers.
+func peerAddrMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), peerAddrCtx, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// clientAddr returns the address of the client, as passed on by the
+// reverse proxy only if proxy headers are trusted.
+func (app *application) clientAddr(r *http.Request) string {
+	if peerAddr, ok := r.Context().Value(peerAddrCtx).(string); ok && !app.config.trustProxyHeaders {
+		return peerAddr
+	}
+
+	return r.RemoteAddr
+}
+
+// clientIP returns the address of the client at remoteAddr, without a port.
+func clientIP(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+
+	return remoteAddr
+}
+
+// ipAllowed reports whether the client at remoteAddr, with or without a
+// port, is in one of the allowed CIDR blocks.
+func ipAllowed(remoteAddr string, allowedIPs []string) bool {
+	addr, err := netip.ParseAddr(remoteAddr)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(remoteAddr)
+		if err != nil {
+			return false
+		}
+		addr = addrPort.Addr()
+	}
+	addr = addr.Unmap()
+
+	for _, allowed := range allowedIPs {
+		prefix, err := netip.ParsePrefix(allowed)
+		if err != nil {
+			continue
+		}
+		if prefix.Masked().Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The access cookie of a status page holds its expiry and a signature over
+// the page and its password hash, so that changing the password logs
+// everyone out.
+func accessCookieName(statusPage *store.StatusPage) string {
+	return "status_page_" + statusPage.ID
+}
+
+func accessCookieParts(statusPage *store.StatusPage, expires string) []string {
+	return []string{"status-page", statusPage.ID, expires, string(statusPage.Password.Hash())}
+}
+
+func (app *application) validAccessCookie(r *http.Request, statusPage *store.StatusPage) bool {
+	cookie, err := r.Cookie(accessCookieName(statusPage))
+	if err != nil {
+		return false
+	}
+
+	expires, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !app.signer.Verify(signature, accessCookieParts(statusPage, expires)...) {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.Now().Before(time.Unix(unix, 0))
+}
+
+// statusPageLoginHandler checks the password entered in the login form of a
+// protected status page, and lets the visitor in with a signed cookie.
+func (app *application) statusPageLoginHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+	pageURL := statusPagePath(r, statusPage)
+
+	if !statusPage.Password.IsSet() {
+		http.Redirect(w, r, pageURL, http.StatusSeeOther)
+		return
+	}
+
+	// Each attempt costs a bcrypt comparison, so guessing is throttled
+	// before the password is checked.
+	now := time.Now()
+	allowed, retryAfter := app.loginsPerIP.Allow(clientIP(app.clientAddr(r)), now)
+	if allowed {
+		allowed, retryAfter = app.loginsPerPage.Allow(statusPage.ID, now)
+	}
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		app.render(w, r, http.StatusTooManyRequests, "login.html", loginView{
+			Name:              statusPage.Name,
+			Slug:              statusPage.Slug,
+			PasswordProtected: true,
+			Error:             "Too many attempts, please try again later.",
+		})
+		return
+	}
+
+	ok, err := statusPage.Password.Matches(r.PostFormValue("password"))
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if !ok {
+		app.render(w, r, http.StatusUnauthorized, "login.html", loginView{
+			Name:              statusPage.Name,
+			Slug:              statusPage.Slug,
+			PasswordProtected: true,
+			Error:             "Incorrect password, please try again.",
+		})
+		return
+	}
+
+	expiresAt := time.Now().Add(statusPageAccessDuration)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     accessCookieName(statusPage),
+		Value:    fmt.Sprintf("%s.%s", expires, app.signer.Sign(accessCookieParts(statusPage, expires)...)),
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil || strings.HasPrefix(app.config.publicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, pageURL, http.StatusSeeOther)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type statusPageKey string
+
+const statusPageCtx statusPageKey = "statusPage"
+
+type CreateStatusPagePayload struct {
+	Name       string   `json:"name" validate:"required,max=100"`
+	Slug       string   `json:"slug" validate:"required,max=100,slug"`
+	MonitorIDs []string `json:"monitors" validate:"unique,dive,required"`
+	// Password protects the page with a password shared by its visitors.
+	Password   string   `json:"password" validate:"omitempty,min=8,max=72"`
+	AllowedIPs []string `json:"allowed_ips" validate:"max=100,dive,cidr"`
+	// Hostname is a custom domain to serve the page on, once verified.
+	Hostname string `json:"hostname" validate:"omitempty,max=253,fqdn"`
+}
+
+// CreateStatusPage godoc
+//
+//	@Summary		Create Status Page
+//	@Description	Create a new status page showing some of your monitors
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		main.CreateStatusPagePayload	true	"CreateStatusPagePayload"
+//	@Success		201		{object}	store.StatusPage
+//	@Failure		400		{object}	error
+//	@Failure		409		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages [post]
+func (app *application) createStatusPageHandler(w http.ResponseWriter, r *http.Request) {
+	var payload CreateStatusPagePayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	//	@TODO:	Change this once auth is implemented
+	userId := "1"
+
+	statusPage := &store.StatusPage{
+		ID:         id,
+		UserID:     userId,
+		Name:       payload.Name,
+		Slug:       payload.Slug,
+		MonitorIDs: payload.MonitorIDs,
+		AllowedIPs: payload.AllowedIPs,
+		Hostname:   strings.ToLower(payload.Hostname),
+	}
+	if statusPage.MonitorIDs == nil {
+		statusPage.MonitorIDs = []string{}
+	}
+	if statusPage.AllowedIPs == nil {
+		statusPage.AllowedIPs = []string{}
+	}
+
+	if payload.Password != "" {
+		if err := statusPage.Password.Set(payload.Password); err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		statusPage.PasswordProtected = true
+	}
+
+	if statusPage.Hostname != "" && statusPage.Hostname == app.publicHostname() {
+		app.failedValidationError(w, r, errors.New("hostname is taken"), map[string]string{
+			"hostname": "must differ from the hostname of the server",
+		})
+		return
+	}
+
+	if err := ensureHostnameToken(statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	if fields, err := app.checkMonitorOwnership(ctx, userId, statusPage.MonitorIDs); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	} else if len(fields) > 0 {
+		app.failedValidationError(w, r, errors.New("monitors do not belong to the user"), fields)
+		return
+	}
+
+	if err := app.store.StatusPages.Create(ctx, statusPage); err != nil {
+		switch {
+		case errors.Is(err, store.ErrDuplicateSlug), errors.Is(err, store.ErrDuplicateHostname):
+			app.conflictError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetStatusPage godoc
+//
+//	@Summary		Get Status Page
+//	@Description	Get Status Page by ID
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{object}	store.StatusPage
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id} [get]
+func (app *application) getStatusPageHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	if err := app.jsonResponse(w, http.StatusOK, statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// ListStatusPages godoc
+//
+//	@Summary		List Status Pages
+//	@Description	List your status pages
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}		store.StatusPage
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages [get]
+func (app *application) listStatusPagesHandler(w http.ResponseWriter, r *http.Request) {
+	//	@TODO:	Change this once auth is implemented
+	userId := "1"
+
+	statusPages, err := app.store.StatusPages.List(r.Context(), userId)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, statusPages); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+type UpdateStatusPagePayload struct {
+	Name       *string  `json:"name" validate:"omitempty,max=100"`
+	Slug       *string  `json:"slug" validate:"omitempty,max=100,slug"`
+	MonitorIDs []string `json:"monitors" validate:"omitempty,unique,dive,required"`
+	// Password replaces the password of the page. An empty password removes
+	// it.
+	Password *string `json:"password" validate:"omitempty,max=72"`
+	// AllowedIPs, when given, replace the allowed CIDR blocks. An empty list
+	// removes them.
+	AllowedIPs []string `json:"allowed_ips" validate:"omitempty,max=100,dive,cidr"`
+	// Hostname replaces the custom domain of the page, which then has to be
+	// verified again. An empty hostname removes it.
+	Hostname *string `json:"hostname" validate:"omitempty,max=253"`
+}
+
+// UpdateStatusPage godoc
+//
+//	@Summary		Update Status Page
+//	@Description	Update a status page. Monitors, when given, replace the attached ones.
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Status Page ID"
+//	@Param			payload	body		main.UpdateStatusPagePayload	true	"UpdateStatusPagePayload"
+//	@Success		200		{object}	store.StatusPage
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		409		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id} [patch]
+func (app *application) updateStatusPageHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	var payload UpdateStatusPagePayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if payload.Name != nil {
+		statusPage.Name = *payload.Name
+	}
+
+	if payload.Slug != nil {
+		statusPage.Slug = *payload.Slug
+	}
+
+	if payload.Password != nil {
+		switch {
+		case *payload.Password == "":
+			statusPage.Password.Clear()
+		case len(*payload.Password) < 8:
+			app.failedValidationError(w, r, errors.New("password is too short"), map[string]string{
+				"password": "must be at least 8 characters long, or empty to remove it",
+			})
+			return
+		default:
+			if err := statusPage.Password.Set(*payload.Password); err != nil {
+				app.internalServerError(w, r, err)
+				return
+			}
+		}
+		statusPage.PasswordProtected = statusPage.Password.IsSet()
+	}
+
+	if payload.AllowedIPs != nil {
+		statusPage.AllowedIPs = payload.AllowedIPs
+	}
+
+	if payload.Hostname != nil {
+		hostname := strings.ToLower(*payload.Hostname)
+		if hostname != "" {
+			if err := Validate.Var(hostname, "fqdn"); err != nil {
+				app.failedValidationError(w, r, err, map[string]string{
+					"hostname": "must be a fully qualified domain name, or empty to remove it",
+				})
+				return
+			}
+			if hostname == app.publicHostname() {
+				app.failedValidationError(w, r, errors.New("hostname is taken"), map[string]string{
+					"hostname": "must differ from the hostname of the server",
+				})
+				return
+			}
+		}
+
+		if hostname != statusPage.Hostname {
+			statusPage.Hostname = hostname
+			statusPage.HostnameVerifiedAt = nil
+			statusPage.HostnameToken = ""
+		}
+	}
+
+	if err := ensureHostnameToken(statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	if payload.MonitorIDs != nil {
+		if fields, err := app.checkMonitorOwnership(ctx, statusPage.UserID, payload.MonitorIDs); err != nil {
+			app.internalServerError(w, r, err)
+			return
+		} else if len(fields) > 0 {
+			app.failedValidationError(w, r, errors.New("monitors do not belong to the user"), fields)
+			return
+		}
+
+		statusPage.MonitorIDs = payload.MonitorIDs
+	}
+
+	if err := app.store.StatusPages.Update(ctx, statusPage); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		case errors.Is(err, store.ErrDuplicateSlug), errors.Is(err, store.ErrDuplicateHostname):
+			app.conflictError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, statusPage); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// DeleteStatusPage godoc
+//
+//	@Summary		Delete Status Page
+//	@Description	Delete Status Page by ID
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Status Page ID"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id} [delete]
+func (app *application) deleteStatusPageHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	if err := app.store.StatusPages.Delete(r.Context(), statusPage.ID); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkMonitorOwnership returns a field error for every monitor in
+// monitorIDs that does not exist or belongs to another user.
+func (app *application) checkMonitorOwnership(ctx context.Context, userID string, monitorIDs []string) (map[string]string, error) {
+	monitors, err := app.store.Monitors.ListByIDs(ctx, monitorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]bool, len(monitors))
+	for _, monitor := range monitors {
+		owned[monitor.ID] = monitor.UserId == userID
+	}
+
+	fields := make(map[string]string)
+	for i, monitorID := range monitorIDs {
+		if !owned[monitorID] {
+			fields[fmt.Sprintf("monitors[%d]", i)] = "is not one of your monitors"
+		}
+	}
+
+	return fields, nil
+}
+
+func (app *application) statusPageContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			app.badRequestError(w, r, errors.New("missing id parameter"))
+			return
+		}
+
+		ctx := r.Context()
+
+		statusPage, err := app.store.StatusPages.GetByID(ctx, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.notFoundError(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		//	@TODO:	Change this once auth is implemented
+		userId := "1"
+
+		// Other users' status pages are reported as missing rather than
+		// forbidden, so their IDs are not revealed.
+		if statusPage.UserID != userId {
+			app.notFoundError(w, r, store.ErrNotFound)
+			return
+		}
+
+		ctx = context.WithValue(ctx, statusPageCtx, statusPage)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getStatusPageFromContext(r *http.Request) *store.StatusPage {
+	statusPage, _ := r.Context().Value(statusPageCtx).(*store.StatusPage)
+	return statusPage
+}
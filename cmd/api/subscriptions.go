@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// Subscribing is throttled per client, allowing a burst of attempts and then
+// one attempt per interval. On top of that, an unconfirmed address is sent
+// at most one confirmation email per confirmationResendInterval.
+const (
+	subscribeBurstPerIP        = 5
+	subscribeIntervalPerIP     = time.Minute
+	confirmationResendInterval = 10 * time.Minute
+)
+
+// messageView is the data of message.html, a page telling visitors the
+// outcome of what they did. With Action set, it shows a form that posts to
+// Action instead, for steps that need to be confirmed with a click.
+type messageView struct {
+	Title   string
+	Message string
+	Action  string
+	Button  string
+	// Back links to the status page the message is about.
+	Back string
+}
+
+type CreateSubscriptionPayload struct {
+	Email string `json:"email" validate:"required,email,max=254"`
+}
+
+type CreateSubscriberPayload struct {
+	WebhookURL string `json:"webhook_url" validate:"required,http_url,max=2048"`
+}
+
+// SubscriptionResponse is returned for webhook subscribers, who do not get an
+// email with the unsubscribe link.
+type SubscriptionResponse struct {
+	*store.Subscriber
+	UnsubscribeURL string `json:"unsubscribe_url"`
+}
+
+// CreateSubscription godoc
+//
+//	@Summary		Subscribe to Status Page
+//	@Description	Subscribe to email notifications about the monitors and posts of a status page. Subscribers are sent a link to confirm their address first, and the response does not reveal whether they were already subscribed.
+//	@Tags			public
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug	path		string							true	"Status page slug"
+//	@Param			payload	body		main.CreateSubscriptionPayload	true	"CreateSubscriptionPayload"
+//	@Success		202		{object}	string
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		429		{object}	error
+//	@Failure		500		{object}	error
+//	@Router			/public/status-pages/{slug}/subscriptions [post]
+func (app *application) createSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	if allowed, retryAfter := app.subscriptionsPerIP.Allow(clientIP(app.clientAddr(r)), time.Now()); !allowed {
+		app.rateLimitExceededError(w, r, retryAfter)
+		return
+	}
+
+	var payload CreateSubscriptionPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := app.subscribeEmail(r.Context(), statusPage, payload.Email); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusAccepted, "Check your inbox to confirm the subscription."); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// CreateSubscriber godoc
+//
+//	@Summary		Create Subscriber
+//	@Description	Add a webhook subscriber to a status page. Webhooks are confirmed right away, and are never sent to loopback, link-local or private addresses.
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Status Page ID"
+//	@Param			payload	body		main.CreateSubscriberPayload	true	"CreateSubscriberPayload"
+//	@Success		201		{object}	main.SubscriptionResponse
+//	@Failure		400		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		409		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/subscribers [post]
+func (app *application) createSubscriberHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	var payload CreateSubscriberPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestError(w, r, err)
+		return
+	}
+
+	id, err := gonanoid.New()
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	subscriber := &store.Subscriber{
+		ID:           id,
+		StatusPageID: statusPage.ID,
+		Kind:         store.SubscriberWebhook,
+		Target:       payload.WebhookURL,
+		ConfirmedAt:  &now,
+	}
+
+	if err := app.store.Subscribers.Create(r.Context(), subscriber); err != nil {
+		switch {
+		case errors.Is(err, store.ErrDuplicateSubscriber):
+			app.conflictError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	response := SubscriptionResponse{
+		Subscriber:     subscriber,
+		UnsubscribeURL: app.notifier.UnsubscribeURL(subscriber),
+	}
+
+	if err := app.jsonResponse(w, http.StatusCreated, response); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// subscribeEmail adds an unconfirmed email subscriber to statusPage and
+// queues the confirmation link for them. Subscribing again only resends the
+// link, until the address has been confirmed, and no more often than every
+// confirmationResendInterval.
+func (app *application) subscribeEmail(ctx context.Context, statusPage *store.StatusPage, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	subscriber, err := app.store.Subscribers.GetByTarget(ctx, statusPage.ID, store.SubscriberEmail, email)
+	switch {
+	case err == nil:
+		if subscriber.ConfirmedAt != nil {
+			return nil
+		}
+
+	case errors.Is(err, store.ErrNotFound):
+		id, err := gonanoid.New()
+		if err != nil {
+			return err
+		}
+
+		subscriber = &store.Subscriber{
+			ID:           id,
+			StatusPageID: statusPage.ID,
+			Kind:         store.SubscriberEmail,
+			Target:       email,
+		}
+
+		if err := app.store.Subscribers.Create(ctx, subscriber); err != nil {
+			return err
+		}
+
+	default:
+		return err
+	}
+
+	send, err := app.store.Subscribers.MarkConfirmationSent(ctx, subscriber, time.Now().Add(-confirmationResendInterval))
+	if err != nil {
+		return err
+	}
+
+	if send {
+		app.notifier.SendConfirmation(statusPage, subscriber)
+	}
+
+	return nil
+}
+
+// subscribeHTMLHandler handles the subscription form of the public status
+// page.
+func (app *application) subscribeHTMLHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	view := messageView{
+		Title: "Subscribe to " + statusPage.Name,
+		Back:  app.notifier.StatusPageURL(statusPage),
+	}
+
+	if allowed, retryAfter := app.subscriptionsPerIP.Allow(clientIP(app.clientAddr(r)), time.Now()); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		view.Message = "Too many attempts, please try again later."
+		app.render(w, r, http.StatusTooManyRequests, "message.html", view)
+		return
+	}
+
+	email := r.PostFormValue("email")
+	if err := Validate.Var(email, "required,email,max=254"); err != nil {
+		view.Message = "Please enter a valid email address."
+		app.render(w, r, http.StatusBadRequest, "message.html", view)
+		return
+	}
+
+	if err := app.subscribeEmail(r.Context(), statusPage, email); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	view.Message = "Almost done! We have sent a confirmation link to " + email + "."
+	app.render(w, r, http.StatusAccepted, "message.html", view)
+}
+
+// confirmSubscriptionHandler opts in an email subscriber who followed the
+// signed link they were sent.
+func (app *application) confirmSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	subscriber, statusPage := getSubscriberFromContext(r)
+
+	if err := app.store.Subscribers.Confirm(r.Context(), subscriber); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.renderInvalidLink(w, r)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	app.render(w, r, http.StatusOK, "message.html", messageView{
+		Title:   "Subscription confirmed",
+		Message: "You will be notified about " + statusPage.Name + ".",
+		Back:    app.notifier.StatusPageURL(statusPage),
+	})
+}
+
+// unsubscribeHandler removes a subscriber who followed the signed link
+// they were sent. Visiting the link only asks to confirm, so that links
+// opened by mail scanners do not unsubscribe anyone; posting to it, as mail
+// clients do for one-click unsubscribe, removes the subscriber.
+func (app *application) unsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	subscriber, statusPage := getSubscriberFromContext(r)
+
+	view := messageView{
+		Title: "Unsubscribe",
+		Back:  app.notifier.StatusPageURL(statusPage),
+	}
+
+	if r.Method != http.MethodPost {
+		view.Message = "Stop notifications about " + statusPage.Name + " to " + subscriber.Target + "?"
+		view.Action = app.notifier.UnsubscribeURL(subscriber)
+		view.Button = "Unsubscribe"
+		app.render(w, r, http.StatusOK, "message.html", view)
+		return
+	}
+
+	if err := app.store.Subscribers.Delete(r.Context(), subscriber.ID); err != nil && !errors.Is(err, store.ErrNotFound) {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	view.Message = "You will no longer be notified about " + statusPage.Name + "."
+	app.render(w, r, http.StatusOK, "message.html", view)
+}
+
+func (app *application) renderInvalidLink(w http.ResponseWriter, r *http.Request) {
+	app.render(w, r, http.StatusNotFound, "message.html", messageView{
+		Title:   "Invalid link",
+		Message: "This link is invalid, or the subscription no longer exists.",
+	})
+}
+
+// ListSubscribers godoc
+//
+//	@Summary		List Subscribers
+//	@Description	List the email and webhook subscribers of a status page, including email subscribers who have not confirmed yet
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Status Page ID"
+//	@Success		200	{array}		store.Subscriber
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/subscribers [get]
+func (app *application) listSubscribersHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	subscribers, err := app.store.Subscribers.List(r.Context(), store.SubscriberFilter{StatusPageID: statusPage.ID})
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	if err := app.jsonResponse(w, http.StatusOK, subscribers); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// DeleteSubscriber godoc
+//
+//	@Summary		Delete Subscriber
+//	@Description	Remove a subscriber from a status page
+//	@Tags			status-pages
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path	string	true	"Status Page ID"
+//	@Param			subscriberId	path	string	true	"Subscriber ID"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		Bearer
+//	@Router			/status-pages/{id}/subscribers/{subscriberId} [delete]
+func (app *application) deleteSubscriberHandler(w http.ResponseWriter, r *http.Request) {
+	statusPage := getStatusPageFromContext(r)
+
+	ctx := r.Context()
+
+	subscriber, err := app.store.Subscribers.GetByID(ctx, chi.URLParam(r, "subscriberId"))
+	if err == nil && subscriber.StatusPageID != statusPage.ID {
+		err = store.ErrNotFound
+	}
+	if err == nil {
+		err = app.store.Subscribers.Delete(ctx, subscriber.ID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundError(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type subscriberKey string
+
+const subscriberCtx subscriberKey = "subscriber"
+
+// subscriptionLinkMiddleware loads the subscriber of a confirm or unsubscribe
+// link, named by its last path segment, together with their status page. Links
+// with a missing or forged token are rejected.
+func (app *application) subscriptionLinkMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		subscriber, err := app.store.Subscribers.GetByID(ctx, chi.URLParam(r, "subscriberId"))
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.renderInvalidLink(w, r)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		action := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if !app.notifier.Verify(subscriber, action, r.URL.Query().Get("token")) {
+			app.renderInvalidLink(w, r)
+			return
+		}
+
+		statusPage, err := app.store.StatusPages.GetByID(ctx, subscriber.StatusPageID)
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrNotFound):
+				app.renderInvalidLink(w, r)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		ctx = context.WithValue(ctx, subscriberCtx, subscriber)
+		ctx = context.WithValue(ctx, statusPageCtx, statusPage)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func getSubscriberFromContext(r *http.Request) (*store.Subscriber, *store.StatusPage) {
+	subscriber, _ := r.Context().Value(subscriberCtx).(*store.Subscriber)
+	return subscriber, getStatusPageFromContext(r)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/statuspage"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// pages lists the templates rendered as whole pages. Each is parsed together
+// with the shared layout into its own set, so that pages can fill in the
+// layout's blocks differently.
+var pages = []string{"status.html", "not_found.html", "message.html", "login.html"}
+
+var templateFuncs = template.FuncMap{
+	"percent": func(uptime *float64) string {
+		if uptime == nil {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", *uptime)
+	},
+	"uptimeClass": func(uptime *float64) string {
+		switch {
+		case uptime == nil:
+			return statuspage.StatusUnknown
+		case *uptime >= 99:
+			return checker.StatusUp
+		case *uptime >= 95:
+			return checker.StatusDegraded
+		default:
+			return checker.StatusDown
+		}
+	},
+	"statusSummary": func(status string) string {
+		switch status {
+		case checker.StatusUp:
+			return "All systems operational"
+		case checker.StatusDegraded:
+			return "Some systems are degraded"
+		case checker.StatusDown:
+			return "Some systems are down"
+		case statuspage.StatusMaintenance:
+			return "Some systems are under maintenance"
+		default:
+			return "No status available yet"
+		}
+	},
+	"componentStatus": func(status string) string {
+		switch status {
+		case checker.StatusUp:
+			return "Operational"
+		case checker.StatusDegraded:
+			return "Degraded performance"
+		case checker.StatusDown:
+			return "Outage"
+		case statuspage.StatusMaintenance:
+			return "Under maintenance"
+		default:
+			return "Unknown"
+		}
+	},
+	"formatTime": func(value any) string {
+		switch t := value.(type) {
+		case time.Time:
+			return t.UTC().Format("Jan 2, 2006 15:04 UTC")
+		case *time.Time:
+			if t == nil {
+				return ""
+			}
+			return t.UTC().Format("Jan 2, 2006 15:04 UTC")
+		default:
+			return ""
+		}
+	},
+	"formatDuration": func(seconds *int64) string {
+		if seconds == nil {
+			return ""
+		}
+		return (time.Duration(*seconds) * time.Second).String()
+	},
+	"postClass": func(post statuspage.PostView) string {
+		switch {
+		case post.Status == store.PostResolved || post.Status == store.PostCompleted:
+			return checker.StatusUp
+		case post.Severity == "minor":
+			return checker.StatusDegraded
+		case post.Severity != "":
+			return checker.StatusDown
+		default:
+			return statuspage.StatusMaintenance
+		}
+	},
+	"postStatus": func(status string) string {
+		return strings.ReplaceAll(status, "_", " ")
+	},
+	"incidentDays": func() int {
+		return statuspage.IncidentDays
+	},
+}
+
+func parseTemplates() (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(pages))
+
+	for _, page := range pages {
+		tmpl, err := template.New(page).Funcs(templateFuncs).ParseFS(templateFS, "templates/layout.html", "templates/"+page)
+		if err != nil {
+			return nil, err
+		}
+		templates[page] = tmpl
+	}
+
+	return templates, nil
+}
+
+// render writes page with data. The page is rendered into a buffer first, so
+// that a failing template results in a clean error response.
+func (app *application) render(w http.ResponseWriter, r *http.Request, status int, page string, data any) {
+	tmpl, ok := app.templates[page]
+	if !ok {
+		app.internalServerError(w, r, fmt.Errorf("template %s does not exist", page))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
@@ -0,0 +1,19 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `monitor_certificates` table, holding the
+-- certificate seen by the most recent TLS check of each monitor
+CREATE TABLE IF NOT EXISTS monitor_certificates (
+    monitor_id TEXT PRIMARY KEY NOT NULL,
+    subject TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    sans TEXT NOT NULL,
+    not_before TIMESTAMP NOT NULL,
+    not_after TIMESTAMP NOT NULL,
+    days_until_expiry INTEGER NOT NULL,
+    hostname_match BOOLEAN NOT NULL,
+    chain_valid BOOLEAN NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_monitors_push_token;
+
+ALTER TABLE monitors
+DROP COLUMN push_token;
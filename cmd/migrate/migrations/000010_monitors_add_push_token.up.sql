@@ -0,0 +1,5 @@
+-- Add `push_token` column to the `monitors` table, holding the secret that
+-- push monitors receive heartbeats through
+ALTER TABLE monitors ADD COLUMN push_token TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_push_token ON monitors (push_token);
@@ -0,0 +1,2 @@
+ALTER TABLE ping_results
+DROP COLUMN message;
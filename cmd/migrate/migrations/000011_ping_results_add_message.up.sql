@@ -0,0 +1,3 @@
+-- Add `message` column to the `ping_results` table, describing why a check
+-- failed or what a heartbeat reported
+ALTER TABLE ping_results ADD COLUMN message TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE monitors
+DROP COLUMN retry_interval;
+
+ALTER TABLE monitors
+DROP COLUMN retries;
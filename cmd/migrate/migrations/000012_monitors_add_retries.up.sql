@@ -0,0 +1,4 @@
+-- Add retry settings to the `monitors` table. A failing monitor is re-tested
+-- `retries` times, `retry_interval` seconds apart, before it is confirmed down.
+ALTER TABLE monitors ADD COLUMN retries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN retry_interval INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_incidents_updated_at;
+DROP INDEX IF EXISTS idx_incidents_started_at;
+DROP INDEX IF EXISTS idx_incidents_monitor_id_started_at;
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,31 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `incidents` table. An incident is opened when a
+-- monitor is confirmed down and resolved when it recovers.
+CREATE TABLE IF NOT EXISTS incidents (
+    id TEXT PRIMARY KEY NOT NULL,
+    monitor_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    duration INTEGER,
+    first_error TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_monitor_id_started_at ON incidents (monitor_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_started_at ON incidents (started_at);
+
+-- Trigger to automatically update `updated_at` timestamp on record update
+CREATE TRIGGER IF NOT EXISTS update_incidents_updated_at
+AFTER UPDATE ON incidents
+FOR EACH ROW
+BEGIN
+    UPDATE incidents
+    SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = OLD.id;
+END;
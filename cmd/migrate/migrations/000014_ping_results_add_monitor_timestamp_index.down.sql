@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_ping_results_monitor_id_timestamp;
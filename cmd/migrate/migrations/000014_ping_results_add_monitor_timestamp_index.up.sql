@@ -0,0 +1,2 @@
+-- Index ping results by monitor and time, for reading the history of a monitor
+CREATE INDEX IF NOT EXISTS idx_ping_results_monitor_id_timestamp ON ping_results (monitor_id, timestamp);
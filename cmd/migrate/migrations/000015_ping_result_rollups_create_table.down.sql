@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ping_results_timestamp;
+DROP TABLE IF EXISTS rollup_watermarks;
+DROP TABLE IF EXISTS ping_result_rollups;
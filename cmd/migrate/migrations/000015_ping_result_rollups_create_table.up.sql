@@ -0,0 +1,33 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `ping_result_rollups` table. Each row aggregates the
+-- confirmed results of a monitor within one minute, hour or day bucket.
+-- `response_times` is a JSON histogram of the response times of the checks
+-- that were up or degraded, so that buckets can be merged.
+CREATE TABLE IF NOT EXISTS ping_result_rollups (
+    monitor_id TEXT NOT NULL,
+    resolution TEXT NOT NULL,
+    bucket TIMESTAMP NOT NULL,
+    checks INTEGER NOT NULL,
+    up INTEGER NOT NULL,
+    degraded INTEGER NOT NULL,
+    down INTEGER NOT NULL,
+    response_time_sum INTEGER NOT NULL,
+    response_time_p50 INTEGER NOT NULL,
+    response_time_p95 INTEGER NOT NULL,
+    response_time_p99 INTEGER NOT NULL,
+    response_times TEXT NOT NULL DEFAULT '{}',
+    PRIMARY KEY (resolution, monitor_id, bucket),
+    FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+-- Migration to create the `rollup_watermarks` table, recording up to which
+-- time each resolution has been rolled up
+CREATE TABLE IF NOT EXISTS rollup_watermarks (
+    resolution TEXT PRIMARY KEY NOT NULL,
+    rolled_until TIMESTAMP NOT NULL
+);
+
+-- Index ping results by time, for rolling up the results of all monitors
+CREATE INDEX IF NOT EXISTS idx_ping_results_timestamp ON ping_results (timestamp);
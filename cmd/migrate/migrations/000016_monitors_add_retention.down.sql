@@ -0,0 +1,2 @@
+ALTER TABLE monitors
+DROP COLUMN retention;
@@ -0,0 +1,3 @@
+-- Add `retention` column to the `monitors` table. It holds a JSON object
+-- overriding how many days of raw results and rollups are kept for the monitor.
+ALTER TABLE monitors ADD COLUMN retention TEXT;
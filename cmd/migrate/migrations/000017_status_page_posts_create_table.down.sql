@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_status_page_post_updates_post_id;
+DROP TABLE IF EXISTS status_page_post_updates;
+DROP INDEX IF EXISTS idx_status_page_post_monitors_monitor_id;
+DROP TABLE IF EXISTS status_page_post_monitors;
+DROP TRIGGER IF EXISTS update_status_page_posts_updated_at;
+DROP INDEX IF EXISTS idx_status_page_posts_status_page_id_starts_at;
+DROP TABLE IF EXISTS status_page_posts;
@@ -0,0 +1,56 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `status_page_posts` table. A post is either an
+-- incident announcement or a scheduled maintenance shown on a status page.
+-- Incidents start when they are posted and end once resolved; maintenance
+-- runs from `starts_at` to `ends_at`.
+CREATE TABLE IF NOT EXISTS status_page_posts (
+    id TEXT PRIMARY KEY NOT NULL,
+    status_page_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    severity TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_posts_status_page_id_starts_at ON status_page_posts (status_page_id, starts_at);
+
+-- Trigger to automatically update `updated_at` timestamp on record update
+CREATE TRIGGER IF NOT EXISTS update_status_page_posts_updated_at
+AFTER UPDATE ON status_page_posts
+FOR EACH ROW
+BEGIN
+    UPDATE status_page_posts
+    SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = OLD.id;
+END;
+
+-- Create a join table for the monitors affected by a post
+CREATE TABLE IF NOT EXISTS status_page_post_monitors (
+    post_id TEXT NOT NULL,
+    monitor_id TEXT NOT NULL,
+    PRIMARY KEY (post_id, monitor_id),
+    FOREIGN KEY (post_id) REFERENCES status_page_posts (id) ON DELETE CASCADE,
+    FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_post_monitors_monitor_id ON status_page_post_monitors (monitor_id);
+
+-- Migration to create the `status_page_post_updates` table, the timeline of
+-- a post
+CREATE TABLE IF NOT EXISTS status_page_post_updates (
+    id TEXT PRIMARY KEY NOT NULL,
+    post_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (post_id) REFERENCES status_page_posts (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_post_updates_post_id ON status_page_post_updates (post_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_status_page_subscribers_target;
+DROP TABLE IF EXISTS status_page_subscribers;
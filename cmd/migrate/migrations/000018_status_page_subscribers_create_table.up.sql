@@ -0,0 +1,18 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `status_page_subscribers` table. Subscribers are
+-- notified by email or webhook about the monitors and posts of a status page.
+-- Email subscribers are only notified once they have confirmed their address,
+-- so `confirmed_at` stays NULL until then.
+CREATE TABLE IF NOT EXISTS status_page_subscribers (
+    id TEXT PRIMARY KEY NOT NULL,
+    status_page_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    target TEXT NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_status_page_subscribers_target ON status_page_subscribers (status_page_id, kind, target);
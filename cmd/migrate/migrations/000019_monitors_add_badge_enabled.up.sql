@@ -0,0 +1,4 @@
+-- Add the `badge_enabled` column to the `monitors` table. Badges reveal the
+-- status and uptime of a monitor to anyone, so they are off until enabled
+-- per monitor.
+ALTER TABLE monitors ADD COLUMN badge_enabled BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE status_pages
+DROP COLUMN allowed_ips;
+ALTER TABLE status_pages
+DROP COLUMN password_hash;
@@ -0,0 +1,6 @@
+-- Add optional protection to the `status_pages` table. Pages with a
+-- `password_hash` (bcrypt) are only shown after logging in with the shared
+-- password, and pages with `allowed_ips` (a JSON array of CIDR blocks) are
+-- shown to visitors from those networks without logging in.
+ALTER TABLE status_pages ADD COLUMN password_hash BLOB;
+ALTER TABLE status_pages ADD COLUMN allowed_ips TEXT;
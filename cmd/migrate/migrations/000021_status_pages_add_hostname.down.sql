@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_status_pages_verified_hostname;
+ALTER TABLE status_pages
+DROP COLUMN hostname_verified_at;
+ALTER TABLE status_pages
+DROP COLUMN hostname;
@@ -0,0 +1,8 @@
+-- Add custom hostnames to the `status_pages` table. A page is served on its
+-- `hostname` once the owner has proven control of the domain with a TXT
+-- record, which sets `hostname_verified_at`. Until then any page may claim a
+-- hostname, but only one page can have it verified.
+ALTER TABLE status_pages ADD COLUMN hostname TEXT;
+ALTER TABLE status_pages ADD COLUMN hostname_verified_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_status_pages_verified_hostname ON status_pages (hostname) WHERE hostname_verified_at IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_status_page_component_monitors_monitor_id;
+DROP TABLE IF EXISTS status_page_component_monitors;
+DROP TRIGGER IF EXISTS update_status_page_components_updated_at;
+DROP INDEX IF EXISTS idx_status_page_components_group_id;
+DROP INDEX IF EXISTS idx_status_page_components_status_page_id;
+DROP TABLE IF EXISTS status_page_components;
+DROP TRIGGER IF EXISTS update_status_page_component_groups_updated_at;
+DROP INDEX IF EXISTS idx_status_page_component_groups_status_page_id;
+DROP TABLE IF EXISTS status_page_component_groups;
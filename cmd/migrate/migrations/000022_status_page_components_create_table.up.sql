@@ -0,0 +1,71 @@
+-- Enable foreign key constraints
+PRAGMA foreign_keys = ON;
+
+-- Migration to create the `status_page_component_groups` table. Groups are
+-- named sections of a status page, shown in order of `position`.
+CREATE TABLE IF NOT EXISTS status_page_component_groups (
+    id TEXT PRIMARY KEY NOT NULL,
+    status_page_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_component_groups_status_page_id ON status_page_component_groups (status_page_id, position);
+
+-- Trigger to automatically update `updated_at` timestamp on record update
+CREATE TRIGGER IF NOT EXISTS update_status_page_component_groups_updated_at
+AFTER UPDATE ON status_page_component_groups
+FOR EACH ROW
+BEGIN
+    UPDATE status_page_component_groups
+    SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = OLD.id;
+END;
+
+-- Migration to create the `status_page_components` table. A component is a
+-- service shown on a status page, whose status is rolled up from its
+-- monitors by `rule` unless an operator has overridden it. Components of a
+-- deleted group are kept outside of any group.
+CREATE TABLE IF NOT EXISTS status_page_components (
+    id TEXT PRIMARY KEY NOT NULL,
+    status_page_id TEXT NOT NULL,
+    group_id TEXT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    rule TEXT NOT NULL DEFAULT 'all',
+    override_status TEXT NOT NULL DEFAULT '',
+    override_message TEXT NOT NULL DEFAULT '',
+    override_expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (status_page_id) REFERENCES status_pages (id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES status_page_component_groups (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_components_status_page_id ON status_page_components (status_page_id, position);
+CREATE INDEX IF NOT EXISTS idx_status_page_components_group_id ON status_page_components (group_id);
+
+-- Trigger to automatically update `updated_at` timestamp on record update
+CREATE TRIGGER IF NOT EXISTS update_status_page_components_updated_at
+AFTER UPDATE ON status_page_components
+FOR EACH ROW
+BEGIN
+    UPDATE status_page_components
+    SET updated_at = CURRENT_TIMESTAMP
+    WHERE id = OLD.id;
+END;
+
+-- Create a join table for the monitors backing a component
+CREATE TABLE IF NOT EXISTS status_page_component_monitors (
+    component_id TEXT NOT NULL,
+    monitor_id TEXT NOT NULL,
+    PRIMARY KEY (component_id, monitor_id),
+    FOREIGN KEY (component_id) REFERENCES status_page_components (id) ON DELETE CASCADE,
+    FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_page_component_monitors_monitor_id ON status_page_component_monitors (monitor_id);
@@ -0,0 +1,2 @@
+ALTER TABLE status_pages
+DROP COLUMN hostname_token;
@@ -0,0 +1,9 @@
+-- Add the `hostname_token` column to the `status_pages` table. The token is
+-- what the TXT record verifying the hostname of a page must hold. It is
+-- random per page and stored, so that it does not change with the secret key
+-- of the server. Pages that already have a hostname get one here.
+ALTER TABLE status_pages ADD COLUMN hostname_token TEXT;
+
+UPDATE status_pages
+SET hostname_token = lower(hex(randomblob(16)))
+WHERE hostname IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE status_page_subscribers
+DROP COLUMN confirmation_sent_at;
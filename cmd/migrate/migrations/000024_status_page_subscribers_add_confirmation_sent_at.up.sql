@@ -0,0 +1,5 @@
+-- Add the `confirmation_sent_at` column to the `status_page_subscribers`
+-- table. It is when an unconfirmed email subscriber was last sent the link to
+-- confirm their address, so that subscribing again cannot be used to flood
+-- the address with confirmation emails.
+ALTER TABLE status_page_subscribers ADD COLUMN confirmation_sent_at TIMESTAMP;
@@ -0,0 +1,187 @@
+package badge
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Styles of badges, named like their shields.io counterparts.
+const (
+	StyleFlat        = "flat"
+	StyleFlatSquare  = "flat-square"
+	StylePlastic     = "plastic"
+	StyleForTheBadge = "for-the-badge"
+)
+
+var Styles = []string{StyleFlat, StyleFlatSquare, StylePlastic, StyleForTheBadge}
+
+// Named colors, as understood by shields.io.
+var colors = map[string]string{
+	"brightgreen":   "#4c1",
+	"green":         "#97ca00",
+	"yellowgreen":   "#a4a61d",
+	"yellow":        "#dfb317",
+	"orange":        "#fe7d37",
+	"red":           "#e05d44",
+	"blue":          "#007ec6",
+	"grey":          "#555",
+	"gray":          "#555",
+	"lightgrey":     "#9f9f9f",
+	"lightgray":     "#9f9f9f",
+	"success":       "#4c1",
+	"important":     "#fe7d37",
+	"critical":      "#e05d44",
+	"informational": "#007ec6",
+	"inactive":      "#9f9f9f",
+}
+
+var hexColor = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Color returns the CSS color of a named or hex color, and whether it is
+// valid.
+func Color(color string) (string, bool) {
+	if value, ok := colors[strings.ToLower(color)]; ok {
+		return value, true
+	}
+
+	if hexColor.MatchString(color) {
+		return "#" + strings.TrimPrefix(color, "#"), true
+	}
+
+	return "", false
+}
+
+// Badge is a two-part label and message image. Colors are names or hex
+// values accepted by Color.
+type Badge struct {
+	Label      string
+	Message    string
+	Color      string
+	LabelColor string
+	Style      string
+}
+
+// SVG renders the badge.
+func (b *Badge) SVG() []byte {
+	label, message := b.Label, b.Message
+
+	height, fontSize, padding, weight := 20, 11.0, 10.0, "normal"
+	radius := 3
+	switch b.Style {
+	case StyleFlatSquare:
+		radius = 0
+	case StyleForTheBadge:
+		label, message = strings.ToUpper(label), strings.ToUpper(message)
+		height, fontSize, padding, weight = 28, 10, 24, "bold"
+		radius = 0
+	case StylePlastic:
+		height, radius = 18, 4
+	}
+
+	labelText := textWidth(label, fontSize, weight == "bold")
+	messageText := textWidth(message, fontSize, weight == "bold")
+
+	labelWidth := 0.0
+	if label != "" {
+		labelWidth = math.Ceil(labelText + padding)
+	}
+	messageWidth := math.Ceil(messageText + padding)
+	width := labelWidth + messageWidth
+
+	color := fill(b.Color, "#9f9f9f")
+	labelColor := fill(b.LabelColor, "#555")
+
+	title := message
+	if label != "" {
+		title = label + ": " + message
+	}
+
+	baseline := float64(height)/2 + fontSize*0.35
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%d" role="img" aria-label="%s">`,
+		width, height, html.EscapeString(title))
+	fmt.Fprintf(&svg, `<title>%s</title>`, html.EscapeString(title))
+
+	gradient := b.Style == StyleFlat || b.Style == StylePlastic || b.Style == ""
+	if gradient {
+		svg.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	}
+
+	fmt.Fprintf(&svg, `<clipPath id="r"><rect width="%g" height="%d" rx="%d" fill="#fff"/></clipPath>`, width, height, radius)
+	svg.WriteString(`<g clip-path="url(#r)">`)
+	if labelWidth > 0 {
+		fmt.Fprintf(&svg, `<rect width="%g" height="%d" fill="%s"/>`, labelWidth, height, labelColor)
+	}
+	fmt.Fprintf(&svg, `<rect x="%g" width="%g" height="%d" fill="%s"/>`, labelWidth, messageWidth, height, color)
+	if gradient {
+		fmt.Fprintf(&svg, `<rect width="%g" height="%d" fill="url(#s)"/>`, width, height)
+	}
+	svg.WriteString(`</g>`)
+
+	fmt.Fprintf(&svg, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="%g" font-weight="%s" text-rendering="geometricPrecision">`,
+		fontSize, weight)
+	if labelWidth > 0 {
+		text(&svg, label, labelWidth/2, baseline, labelText, gradient)
+	}
+	text(&svg, message, labelWidth+messageWidth/2, baseline, messageText, gradient)
+	svg.WriteString(`</g></svg>`)
+
+	return []byte(svg.String())
+}
+
+// text writes s centered on x, with a drop shadow in the styles that have
+// one.
+func text(svg *strings.Builder, s string, x, y, width float64, shadow bool) {
+	s = html.EscapeString(s)
+
+	if shadow {
+		fmt.Fprintf(svg, `<text x="%g" y="%g" fill="#010101" fill-opacity=".3" textLength="%g">%s</text>`, x, y+1, width, s)
+	}
+	fmt.Fprintf(svg, `<text x="%g" y="%g" textLength="%g">%s</text>`, x, y, width, s)
+}
+
+func fill(color, fallback string) string {
+	if value, ok := Color(color); ok {
+		return value
+	}
+	return fallback
+}
+
+// charWidths are the advance widths of Verdana at 11px. Other characters
+// are assumed to be as wide as charWidth.
+var charWidths = map[rune]float64{
+	' ': 3.87, '!': 4.33, '%': 12.1, '(': 5.46, ')': 5.46, ',': 3.64, '-': 4.57, '.': 3.64, '/': 5.46, ':': 4.57,
+	'0': 7, '1': 7, '2': 7, '3': 7, '4': 7, '5': 7, '6': 7, '7': 7, '8': 7, '9': 7,
+	'a': 6.66, 'b': 6.83, 'c': 5.73, 'd': 6.83, 'e': 6.5, 'f': 3.77, 'g': 6.83, 'h': 6.95, 'i': 3.01,
+	'j': 3.77, 'k': 6.5, 'l': 3.01, 'm': 10.66, 'n': 6.95, 'o': 6.66, 'p': 6.83, 'q': 6.83, 'r': 4.69,
+	's': 5.73, 't': 4.3, 'u': 6.95, 'v': 6.5, 'w': 8.97, 'x': 6.5, 'y': 6.5, 'z': 5.73,
+	'A': 7.5, 'B': 7.55, 'C': 7.68, 'D': 8.46, 'E': 6.96, 'F': 6.31, 'G': 8.5, 'H': 8.26, 'I': 4.62,
+	'J': 5, 'K': 7.6, 'L': 6.14, 'M': 9.28, 'N': 8.22, 'O': 8.64, 'P': 6.63, 'Q': 8.64, 'R': 7.65,
+	'S': 7.52, 'T': 6.78, 'U': 8.04, 'V': 7.5, 'W': 10.88, 'X': 7.52, 'Y': 6.77, 'Z': 7.52,
+}
+
+const charWidth = 7.0
+
+// textWidth estimates the width of s set in Verdana at size pixels.
+func textWidth(s string, size float64, bold bool) float64 {
+	var width float64
+	for _, r := range s {
+		w, ok := charWidths[r]
+		if !ok {
+			w = charWidth
+		}
+		width += w
+	}
+
+	width *= size / 11
+	if bold {
+		// Bold glyphs are wider, and for-the-badge spaces out its letters.
+		width = width*1.1 + float64(len([]rune(s)))*1.25
+	}
+
+	return math.Round(width*10) / 10
+}
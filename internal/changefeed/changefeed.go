@@ -0,0 +1,186 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+type EventType string
+
+const (
+	MonitorCreated EventType = "monitor.created"
+	MonitorUpdated EventType = "monitor.updated"
+	MonitorDeleted EventType = "monitor.deleted"
+)
+
+var ErrCursorExpired = errors.New("cursor is older than the oldest retained event")
+
+// Event describes a single change. Cursors increase by one with every
+// published event, so a consumer that remembers the cursor of the last event
+// it handled can resume exactly where it left off.
+type Event struct {
+	Cursor    uint64         `json:"cursor"`
+	Type      EventType      `json:"type"`
+	MonitorID string         `json:"monitor_id"`
+	Version   int            `json:"version"`
+	Monitor   *store.Monitor `json:"monitor,omitempty"`
+	At        time.Time      `json:"at"`
+}
+
+// Feed keeps the most recent events in memory and fans them out to
+// subscribers. It is safe for concurrent use.
+type Feed struct {
+	mu     sync.Mutex
+	events []Event
+	// start is the index in events of the oldest retained event.
+	start  int
+	cursor uint64
+	// notify is closed and replaced on every publish to wake subscribers.
+	notify chan struct{}
+}
+
+func New(capacity int) *Feed {
+	capacity = max(capacity, 1)
+
+	return &Feed{
+		events: make([]Event, 0, capacity),
+		notify: make(chan struct{}),
+	}
+}
+
+// Publish records a change to monitor. For deletions the monitor is only used
+// for its ID and last known version.
+func (f *Feed) Publish(eventType EventType, monitor *store.Monitor) Event {
+	// Copy so that later edits by the publisher are not seen by consumers.
+	snapshot := *monitor
+
+	event := Event{
+		Type:      eventType,
+		MonitorID: snapshot.ID,
+		Version:   snapshot.Version,
+		At:        time.Now().UTC(),
+	}
+	if eventType != MonitorDeleted {
+		event.Monitor = &snapshot
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.cursor++
+	event.Cursor = f.cursor
+
+	if len(f.events) < cap(f.events) {
+		f.events = append(f.events, event)
+	} else {
+		f.events[f.start] = event
+		f.start = (f.start + 1) % len(f.events)
+	}
+
+	close(f.notify)
+	f.notify = make(chan struct{})
+
+	return event
+}
+
+// Cursor returns the cursor of the most recently published event. Reading it
+// before loading the current state and subscribing from it afterwards
+// guarantees no change is missed in between.
+func (f *Feed) Cursor() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.cursor
+}
+
+// Since returns all events published after cursor, oldest first.
+func (f *Feed) Since(cursor uint64) ([]Event, error) {
+	events, _, err := f.since(cursor)
+	return events, err
+}
+
+func (f *Feed) since(cursor uint64) ([]Event, <-chan struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cursor >= f.cursor {
+		return nil, f.notify, nil
+	}
+
+	oldest := f.cursor - uint64(len(f.events)) + 1
+	if cursor+1 < oldest {
+		return nil, nil, ErrCursorExpired
+	}
+
+	skip := int(cursor + 1 - oldest)
+	events := make([]Event, 0, len(f.events)-skip)
+	for i := skip; i < len(f.events); i++ {
+		events = append(events, f.events[(f.start+i)%len(f.events)])
+	}
+
+	return events, f.notify, nil
+}
+
+type Subscription struct {
+	events chan Event
+	err    error
+}
+
+// Events delivers events in cursor order. The channel is closed when the
+// subscription's context is done or the consumer fell too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err reports why the subscription ended. It is only valid once the events
+// channel has been closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Subscribe delivers every event published after cursor until ctx is done.
+// If the feed no longer retains the events following cursor, the
+// subscription ends with ErrCursorExpired and the consumer has to reload its
+// state before subscribing again.
+func (f *Feed) Subscribe(ctx context.Context, cursor uint64) *Subscription {
+	sub := &Subscription{events: make(chan Event)}
+
+	go func() {
+		defer close(sub.events)
+
+		for {
+			events, notify, err := f.since(cursor)
+			if err != nil {
+				sub.err = err
+				return
+			}
+
+			for _, event := range events {
+				select {
+				case sub.events <- event:
+					cursor = event.Cursor
+				case <-ctx.Done():
+					sub.err = ctx.Err()
+					return
+				}
+			}
+
+			if len(events) > 0 {
+				continue
+			}
+
+			select {
+			case <-notify:
+			case <-ctx.Done():
+				sub.err = ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return sub
+}
@@ -0,0 +1,279 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxAssertedBodySize bounds how much of a response body is read when a
+// monitor asserts on it.
+const maxAssertedBodySize = 1 << 20
+
+const (
+	AssertContains    = "contains"
+	AssertNotContains = "not_contains"
+	AssertRegex       = "regex"
+	AssertNotRegex    = "not_regex"
+	AssertJSONPath    = "json_path"
+)
+
+// BodyAssertion is a rule the response body of an HTTP monitor has to
+// satisfy for the monitor to be up.
+type BodyAssertion struct {
+	Type string `json:"type" validate:"required,oneof=contains not_contains regex not_regex json_path"`
+	// Path selects a value with a JSONPath subset: $, .key, ['key'] and
+	// [index]. Only used by json_path assertions.
+	Path string `json:"path,omitempty"`
+	// Operator compares the selected value with Value. It defaults to ==;
+	// exists only requires the path to be present.
+	Operator string `json:"operator,omitempty" validate:"omitempty,oneof=== != < <= > >= exists"`
+	// Value is the keyword or regular expression to look for, or the JSON
+	// value a path is compared against.
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+func (a *BodyAssertion) validate() map[string]string {
+	fields := map[string]string{}
+
+	switch a.Type {
+	case AssertContains, AssertNotContains, AssertRegex, AssertNotRegex:
+		var value string
+		if err := json.Unmarshal(a.Value, &value); err != nil || value == "" {
+			fields["value"] = "must be a non-empty string"
+			break
+		}
+
+		if a.Type == AssertRegex || a.Type == AssertNotRegex {
+			if _, err := regexp.Compile(value); err != nil {
+				fields["value"] = "must be a valid regular expression"
+			}
+		}
+	case AssertJSONPath:
+		if _, err := parseJSONPath(a.Path); err != nil {
+			fields["path"] = err.Error()
+		}
+
+		switch a.operator() {
+		case "exists":
+		case "<", "<=", ">", ">=":
+			var value float64
+			if err := json.Unmarshal(a.Value, &value); err != nil {
+				fields["value"] = "must be a number"
+			}
+		default:
+			if !json.Valid(a.Value) {
+				fields["value"] = "is required"
+			}
+		}
+	}
+
+	return fields
+}
+
+func (a *BodyAssertion) operator() string {
+	if a.Operator == "" {
+		return "=="
+	}
+
+	return a.Operator
+}
+
+func (a *BodyAssertion) String() string {
+	if a.Type == AssertJSONPath {
+		if a.operator() == "exists" {
+			return fmt.Sprintf("%s exists", a.Path)
+		}
+		return fmt.Sprintf("%s %s %s", a.Path, a.operator(), a.Value)
+	}
+
+	return fmt.Sprintf("%s %s", a.Type, a.Value)
+}
+
+// check reports why body does not satisfy the assertion, or nil if it does.
+func (a *BodyAssertion) check(body []byte) error {
+	var pattern string
+	if a.Type != AssertJSONPath {
+		if err := json.Unmarshal(a.Value, &pattern); err != nil {
+			return err
+		}
+	}
+
+	switch a.Type {
+	case AssertContains:
+		if !bytes.Contains(body, []byte(pattern)) {
+			return errors.New("keyword not found")
+		}
+	case AssertNotContains:
+		if bytes.Contains(body, []byte(pattern)) {
+			return errors.New("forbidden keyword found")
+		}
+	case AssertRegex, AssertNotRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		if matched := re.Match(body); matched != (a.Type == AssertRegex) {
+			if matched {
+				return errors.New("forbidden pattern matched")
+			}
+			return errors.New("pattern not matched")
+		}
+	case AssertJSONPath:
+		return a.checkJSONPath(body)
+	}
+
+	return nil
+}
+
+func (a *BodyAssertion) checkJSONPath(body []byte) error {
+	path, err := parseJSONPath(a.Path)
+	if err != nil {
+		return err
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return errors.New("body is not valid JSON")
+	}
+
+	actual, ok := selectJSONPath(document, path)
+	if !ok {
+		return errors.New("path not found")
+	}
+
+	op := a.operator()
+	if op == "exists" {
+		return nil
+	}
+
+	var expected any
+	if err := json.Unmarshal(a.Value, &expected); err != nil {
+		return err
+	}
+
+	got, _ := json.Marshal(actual)
+
+	switch op {
+	case "==", "!=":
+		if reflect.DeepEqual(actual, expected) != (op == "==") {
+			return fmt.Errorf("got %s", got)
+		}
+	default:
+		actualNum, ok := actual.(float64)
+		expectedNum, _ := expected.(float64)
+		if !ok || !compareNumbers(actualNum, op, expectedNum) {
+			return fmt.Errorf("got %s", got)
+		}
+	}
+
+	return nil
+}
+
+func compareNumbers(a float64, op string, b float64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+
+	return false
+}
+
+// checkAssertions returns an error naming the first assertion body fails.
+func checkAssertions(body []byte, assertions []BodyAssertion) error {
+	for i := range assertions {
+		if err := assertions[i].check(body); err != nil {
+			return fmt.Errorf("assertion %d (%s) failed: %w", i, &assertions[i], err)
+		}
+	}
+
+	return nil
+}
+
+// jsonPathStep is either an object key or, when key is empty, an array index.
+type jsonPathStep struct {
+	key   string
+	index int
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, errors.New("must start with $")
+	}
+
+	var steps []jsonPathStep
+
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, errors.New("must not contain empty keys")
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end == -1 {
+				return nil, errors.New("has an unterminated ['key']")
+			}
+			steps = append(steps, jsonPathStep{key: rest[2:end]})
+			rest = rest[end+2:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, errors.New("has an unterminated [index]")
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, errors.New("must only use non-negative array indexes")
+			}
+			steps = append(steps, jsonPathStep{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("has unexpected %q", rest)
+		}
+	}
+
+	return steps, nil
+}
+
+func selectJSONPath(document any, path []jsonPathStep) (any, bool) {
+	current := document
+
+	for _, step := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[step.key]
+			if step.key == "" || !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			if step.key != "" || step.index >= len(node) {
+				return nil, false
+			}
+			current = node[step.index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
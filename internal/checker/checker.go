@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"context"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+	// StatusPending marks a failed check that is being retried before the
+	// monitor is confirmed down.
+	StatusPending = "pending"
+)
+
+// DefaultTimeout caps how long a single check may take when the monitor
+// does not configure a timeout of its own.
+const DefaultTimeout = time.Second * 30
+
+// MaxTimeout is the longest timeout a monitor may configure.
+const MaxTimeout = time.Second * 300
+
+// Result is the outcome of a single check. An empty Status means the check
+// had nothing to report and no result should be recorded.
+type Result struct {
+	Status       string
+	ResponseTime time.Duration
+	Err          error
+	// Certificate is set by checks that inspected a TLS peer certificate.
+	Certificate *store.Certificate
+}
+
+// Checker runs a single check of a monitor. *Registry implements it by
+// dispatching on the monitor's kind.
+type Checker interface {
+	Check(ctx context.Context, monitor *store.Monitor) Result
+	// Timeout returns the timeout the monitor configures for its checks, or
+	// 0 when it leaves it to the caller.
+	Timeout(monitor *store.Monitor) time.Duration
+	// Forget drops what is kept about the monitor between checks, once it
+	// is deleted or no longer checked the same way.
+	Forget(monitorID string)
+}
+
+func up(start time.Time) Result {
+	return Result{Status: StatusUp, ResponseTime: time.Since(start)}
+}
+
+func down(start time.Time, err error) Result {
+	return Result{Status: StatusDown, ResponseTime: time.Since(start), Err: err}
+}
+
+// statusRank orders statuses from best to worst.
+func statusRank(status string) int {
+	switch status {
+	case StatusUp:
+		return 0
+	case StatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,360 @@
+package checker
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const KindDNS = "dns"
+
+const (
+	defaultResolver = "1.1.1.1:53"
+	// maxUDPSize is the payload size advertised through EDNS(0), which keeps
+	// typical TXT and MX answers from being truncated.
+	maxUDPSize = 1232
+)
+
+const (
+	DNSMatchExact    = "exact"
+	DNSMatchContains = "contains"
+)
+
+var dnsRecordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+	"NS":    dnsmessage.TypeNS,
+}
+
+// DNSConfig is the config of DNS monitors, whose address is the hostname to
+// resolve.
+type DNSConfig struct {
+	RecordType string `json:"record_type" validate:"required,oneof=A AAAA CNAME MX TXT NS"`
+	// Resolver is the host:port of the DNS server to query.
+	Resolver string `json:"resolver,omitempty"`
+	// Expected answers. IPs for A/AAAA, hostnames for CNAME/NS,
+	// "<preference> <host>" for MX and the record text for TXT. When empty,
+	// any successful answer counts as up.
+	Expected []string `json:"expected,omitempty" validate:"dive,required"`
+	// Match is "exact" to require exactly the expected answers, or
+	// "contains" to only require that each of them is present.
+	Match string `json:"match,omitempty" validate:"omitempty,oneof=exact contains"`
+	// Timeout in seconds.
+	Timeout int `json:"timeout,omitempty" validate:"gte=0,lte=300"`
+}
+
+func (c *DNSConfig) Validate() map[string]string {
+	fields := map[string]string{}
+
+	if c.Resolver != "" {
+		if err := validateHostPort(c.Resolver); err != nil {
+			fields["resolver"] = err.Error()
+		}
+	}
+
+	for i, expected := range c.Expected {
+		if _, err := normalizeDNSAnswer(c.RecordType, expected); err != nil {
+			fields[fmt.Sprintf("expected[%d]", i)] = err.Error()
+		}
+	}
+
+	return fields
+}
+
+func (c *DNSConfig) timeout() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
+
+type DNSChecker struct {
+	dialer *net.Dialer
+}
+
+func NewDNSChecker() *DNSChecker {
+	return &DNSChecker{
+		dialer: &net.Dialer{},
+	}
+}
+
+func (c *DNSChecker) Name() string {
+	return KindDNS
+}
+
+func (c *DNSChecker) ValidateAddress(address string) error {
+	if strings.Contains(address, "/") || strings.Contains(address, ":") {
+		return errors.New("must be a hostname")
+	}
+
+	if _, err := dnsmessage.NewName(fqdn(address)); err != nil || address == "" {
+		return errors.New("must be a hostname")
+	}
+
+	return nil
+}
+
+func (c *DNSChecker) NewConfig() Config {
+	return &DNSConfig{}
+}
+
+func (c *DNSChecker) Check(ctx context.Context, monitor *store.Monitor, cfg Config) Result {
+	start := time.Now()
+	config := cfg.(*DNSConfig)
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	resolver := config.Resolver
+	if resolver == "" {
+		resolver = defaultResolver
+	}
+
+	recordType := dnsRecordTypes[config.RecordType]
+
+	msg, err := c.query(ctx, resolver, monitor.Address, recordType)
+	if err != nil {
+		return down(start, err)
+	}
+
+	switch msg.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return down(start, errors.New("NXDOMAIN"))
+	case dnsmessage.RCodeServerFailure:
+		return down(start, errors.New("SERVFAIL"))
+	default:
+		return down(start, fmt.Errorf("resolver returned %s", msg.RCode))
+	}
+
+	answers := dnsAnswers(msg, recordType)
+	if len(answers) == 0 {
+		return down(start, fmt.Errorf("no %s records found", config.RecordType))
+	}
+
+	if len(config.Expected) > 0 {
+		expected := make([]string, 0, len(config.Expected))
+		for _, e := range config.Expected {
+			normalized, err := normalizeDNSAnswer(config.RecordType, e)
+			if err != nil {
+				return down(start, err)
+			}
+			expected = append(expected, normalized)
+		}
+
+		if !dnsAnswersMatch(answers, expected, config.Match) {
+			return down(start, fmt.Errorf("answers drifted: got [%s], expected [%s]",
+				strings.Join(answers, ", "), strings.Join(expected, ", ")))
+		}
+	}
+
+	return up(start)
+}
+
+// query asks resolver for records of recordType over UDP, retrying over TCP
+// when the answer was truncated.
+func (c *DNSChecker) query(ctx context.Context, resolver, host string, recordType dnsmessage.Type) (*dnsmessage.Message, error) {
+	name, err := dnsmessage.NewName(fqdn(host))
+	if err != nil {
+		return nil, err
+	}
+
+	id := uint16(rand.N(1 << 16))
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: name, Type: recordType, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(maxUDPSize, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	if err := builder.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+
+	packed, err := builder.Finish()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.exchange(ctx, "udp", resolver, packed, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Truncated {
+		return c.exchange(ctx, "tcp", resolver, packed, id)
+	}
+
+	return msg, nil
+}
+
+func (c *DNSChecker) exchange(ctx context.Context, network, resolver string, packed []byte, id uint16) (*dnsmessage.Message, error) {
+	conn, err := c.dialer.DialContext(ctx, network, resolver)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var reply []byte
+
+	if network == "tcp" {
+		// Messages over TCP are prefixed with their length.
+		framed := binary.BigEndian.AppendUint16(nil, uint16(len(packed)))
+		if _, err := conn.Write(append(framed, packed...)); err != nil {
+			return nil, err
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+
+		reply = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(packed); err != nil {
+			return nil, err
+		}
+
+		reply = make([]byte, maxUDPSize)
+		n, err := conn.Read(reply)
+		if err != nil {
+			return nil, err
+		}
+		reply = reply[:n]
+	}
+
+	var msg dnsmessage.Message
+	if err := msg.Unpack(reply); err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+
+	if !msg.Response || msg.ID != id {
+		return nil, errors.New("invalid DNS response: mismatched id")
+	}
+
+	return &msg, nil
+}
+
+// dnsAnswers returns the normalized answers of recordType, sorted. Records of
+// other types, such as the CNAMEs leading to an A record, are skipped.
+func dnsAnswers(msg *dnsmessage.Message, recordType dnsmessage.Type) []string {
+	var answers []string
+
+	for _, answer := range msg.Answers {
+		if answer.Header.Type != recordType {
+			continue
+		}
+
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			answers = append(answers, netip.AddrFrom4(body.A).String())
+		case *dnsmessage.AAAAResource:
+			answers = append(answers, netip.AddrFrom16(body.AAAA).String())
+		case *dnsmessage.CNAMEResource:
+			answers = append(answers, normalizeDNSName(body.CNAME.String()))
+		case *dnsmessage.NSResource:
+			answers = append(answers, normalizeDNSName(body.NS.String()))
+		case *dnsmessage.MXResource:
+			answers = append(answers, fmt.Sprintf("%d %s", body.Pref, normalizeDNSName(body.MX.String())))
+		case *dnsmessage.TXTResource:
+			answers = append(answers, strings.Join(body.TXT, ""))
+		}
+	}
+
+	slices.Sort(answers)
+
+	return answers
+}
+
+// normalizeDNSAnswer brings an expected answer into the form dnsAnswers
+// produces so the two can be compared as strings.
+func normalizeDNSAnswer(recordType, answer string) (string, error) {
+	switch recordType {
+	case "A":
+		addr, err := netip.ParseAddr(answer)
+		if err != nil || !addr.Is4() {
+			return "", errors.New("must be an IPv4 address")
+		}
+		return addr.String(), nil
+	case "AAAA":
+		addr, err := netip.ParseAddr(answer)
+		if err != nil || !addr.Is6() {
+			return "", errors.New("must be an IPv6 address")
+		}
+		return addr.String(), nil
+	case "CNAME", "NS":
+		return normalizeDNSName(answer), nil
+	case "MX":
+		pref, host, ok := strings.Cut(strings.TrimSpace(answer), " ")
+		n, err := strconv.ParseUint(pref, 10, 16)
+		if !ok || err != nil {
+			return "", errors.New(`must be in "<preference> <host>" form`)
+		}
+		return fmt.Sprintf("%d %s", n, normalizeDNSName(host)), nil
+	default:
+		return answer, nil
+	}
+}
+
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+func dnsAnswersMatch(answers, expected []string, match string) bool {
+	for _, e := range expected {
+		if !slices.Contains(answers, e) {
+			return false
+		}
+	}
+
+	if match == DNSMatchContains {
+		return true
+	}
+
+	for _, answer := range answers {
+		if !slices.Contains(expected, answer) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func fqdn(host string) string {
+	if strings.HasSuffix(host, ".") {
+		return host
+	}
+
+	return host + "."
+}
@@ -0,0 +1,281 @@
+package checker
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsZone answers queries of a test DNS server. Names are fully qualified
+// and lower case. Names in truncate are answered with the truncated bit set
+// and no records over UDP, so that clients retry over TCP.
+type dnsZone struct {
+	a        map[string][][4]byte
+	mx       map[string][]dnsmessage.MXResource
+	txt      map[string][][]string
+	empty    map[string]bool
+	truncate map[string]bool
+}
+
+func (z *dnsZone) answer(query []byte, udp bool) ([]byte, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(q.Name.String())
+	reply := dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true}
+
+	_, knownA := z.a[name]
+	_, knownMX := z.mx[name]
+	_, knownTXT := z.txt[name]
+	if !knownA && !knownMX && !knownTXT && !z.empty[name] {
+		reply.RCode = dnsmessage.RCodeNameError
+	}
+
+	truncated := udp && z.truncate[name]
+	reply.Truncated = truncated
+
+	b := dnsmessage.NewBuilder(nil, reply)
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+
+	if !truncated {
+		switch q.Type {
+		case dnsmessage.TypeA:
+			for _, a := range z.a[name] {
+				if err := b.AResource(rh, dnsmessage.AResource{A: a}); err != nil {
+					return nil, err
+				}
+			}
+		case dnsmessage.TypeMX:
+			for _, mx := range z.mx[name] {
+				if err := b.MXResource(rh, mx); err != nil {
+					return nil, err
+				}
+			}
+		case dnsmessage.TypeTXT:
+			for _, txt := range z.txt[name] {
+				if err := b.TXTResource(rh, dnsmessage.TXTResource{TXT: txt}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return b.Finish()
+}
+
+// serve answers from z over UDP and TCP on the same local port until the
+// test ends, and returns the address of the server.
+func (z *dnsZone) serve(t *testing.T) string {
+	t.Helper()
+
+	var (
+		pc  net.PacketConn
+		l   net.Listener
+		err error
+	)
+	// The TCP port picked for the UDP one may be taken, so try a few.
+	for range 10 {
+		if pc, err = net.ListenPacket("udp", "127.0.0.1:0"); err != nil {
+			t.Fatal(err)
+		}
+		if l, err = net.Listen("tcp", pc.LocalAddr().String()); err == nil {
+			break
+		}
+		pc.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		pc.Close()
+		l.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply, err := z.answer(buf[:n], true); err == nil {
+				pc.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+
+				reply, err := z.answer(query, false)
+				if err != nil {
+					return
+				}
+				conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...))
+			}()
+		}
+	}()
+
+	return pc.LocalAddr().String()
+}
+
+func mustName(t *testing.T, name string) dnsmessage.Name {
+	t.Helper()
+
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDNSChecker(t *testing.T) {
+	zone := &dnsZone{
+		a: map[string][][4]byte{
+			"example.com.": {{192, 0, 2, 2}, {192, 0, 2, 1}},
+		},
+		mx: map[string][]dnsmessage.MXResource{
+			"example.com.": {{Pref: 10, MX: mustName(t, "Mail.Example.com.")}},
+		},
+		txt: map[string][][]string{
+			"example.com.":     {{"v=spf1 ", "-all"}, {"site-verification=abc"}},
+			"big.example.com.": {{"served over tcp"}},
+		},
+		empty:    map[string]bool{"empty.example.com.": true},
+		truncate: map[string]bool{"big.example.com.": true},
+	}
+	resolver := zone.serve(t)
+
+	tests := []struct {
+		name    string
+		address string
+		config  DNSConfig
+		status  string
+		err     string
+	}{
+		{
+			name:    "any answer",
+			address: "example.com",
+			config:  DNSConfig{RecordType: "A"},
+			status:  StatusUp,
+		},
+		{
+			name:    "exact answers in any order",
+			address: "example.com",
+			config:  DNSConfig{RecordType: "A", Expected: []string{"192.0.2.1", "192.0.2.2"}},
+			status:  StatusUp,
+		},
+		{
+			name:    "exact answers missing one",
+			address: "example.com",
+			config:  DNSConfig{RecordType: "A", Expected: []string{"192.0.2.1"}},
+			status:  StatusDown,
+			err:     "answers drifted: got [192.0.2.1, 192.0.2.2], expected [192.0.2.1]",
+		},
+		{
+			name:    "contains answer",
+			address: "example.com",
+			config:  DNSConfig{RecordType: "A", Expected: []string{"192.0.2.1"}, Match: DNSMatchContains},
+			status:  StatusUp,
+		},
+		{
+			name:    "MX normalized",
+			address: "EXAMPLE.com.",
+			config:  DNSConfig{RecordType: "MX", Expected: []string{"10 mail.example.com."}},
+			status:  StatusUp,
+		},
+		{
+			name:    "TXT strings joined",
+			address: "example.com",
+			config:  DNSConfig{RecordType: "TXT", Expected: []string{"v=spf1 -all"}, Match: DNSMatchContains},
+			status:  StatusUp,
+		},
+		{
+			name:    "truncated answer retried over TCP",
+			address: "big.example.com",
+			config:  DNSConfig{RecordType: "TXT", Expected: []string{"served over tcp"}},
+			status:  StatusUp,
+		},
+		{
+			name:    "no records of the type",
+			address: "empty.example.com",
+			config:  DNSConfig{RecordType: "A"},
+			status:  StatusDown,
+			err:     "no A records found",
+		},
+		{
+			name:    "unknown name",
+			address: "missing.example.com",
+			config:  DNSConfig{RecordType: "A"},
+			status:  StatusDown,
+			err:     "NXDOMAIN",
+		},
+	}
+
+	checker := NewDNSChecker()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			config.Resolver = resolver
+			config.Timeout = 5
+
+			if fields := config.Validate(); len(fields) > 0 {
+				t.Fatalf("invalid config: %v", fields)
+			}
+
+			result := checker.Check(context.Background(), &store.Monitor{Address: test.address}, &config)
+
+			if result.Status != test.status {
+				t.Errorf("status = %q (error %v), want %q", result.Status, result.Err, test.status)
+			}
+
+			var err string
+			if result.Err != nil {
+				err = result.Err.Error()
+			}
+			if err != test.err {
+				t.Errorf("error = %q, want %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const defaultMaxRedirects = 10
+
+// maxDrainedBodySize bounds how much of a response body is read only to reuse
+// the connection. Larger bodies close the connection instead.
+const maxDrainedBodySize = 64 << 10
+
+var defaultAcceptedStatusCodes = []string{"200-299"}
+
+const KindHTTP = "http"
+
+// HTTPConfig is the config of HTTP monitors.
+type HTTPConfig struct {
+	Headers map[string]string `json:"headers,omitempty" validate:"dive,keys,required,endkeys"`
+	Body    string            `json:"body,omitempty"`
+	// MaxRedirects limits how many redirects are followed. Zero disables
+	// redirects, so the redirect response itself is evaluated.
+	MaxRedirects *int `json:"max_redirects,omitempty" validate:"omitempty,gte=0,lte=20"`
+	// AcceptedStatusCodes lists single codes ("204") or inclusive ranges
+	// ("200-299") that count as up.
+	AcceptedStatusCodes []string `json:"accepted_status_codes,omitempty"`
+	// Timeout in seconds.
+	Timeout int `json:"timeout,omitempty" validate:"gte=0,lte=300"`
+	// Certificate enables inspection of the server certificate on https
+	// addresses, like a tls monitor would.
+	Certificate *CertificateThresholds `json:"certificate,omitempty"`
+	// Assertions are checked against the response body once the status
+	// code has been accepted.
+	Assertions []BodyAssertion `json:"assertions,omitempty" validate:"dive"`
+}
+
+func (c *HTTPConfig) Validate() map[string]string {
+	fields := map[string]string{}
+
+	if _, err := parseStatusCodes(c.AcceptedStatusCodes); err != nil {
+		fields["accepted_status_codes"] = err.Error()
+	}
+
+	if c.Certificate != nil {
+		for field, msg := range c.Certificate.validate() {
+			fields["certificate."+field] = msg
+		}
+	}
+
+	for i := range c.Assertions {
+		for field, msg := range c.Assertions[i].validate() {
+			fields[fmt.Sprintf("assertions[%d].%s", i, field)] = msg
+		}
+	}
+
+	return fields
+}
+
+func (c *HTTPConfig) timeout() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
+
+type statusCodeRange struct {
+	from int
+	to   int
+}
+
+type HTTPChecker struct {
+	transport http.RoundTripper
+}
+
+func NewHTTPChecker() *HTTPChecker {
+	return &HTTPChecker{
+		transport: http.DefaultTransport,
+	}
+}
+
+func (c *HTTPChecker) Name() string {
+	return KindHTTP
+}
+
+func (c *HTTPChecker) ValidateAddress(address string) error {
+	u, err := url.ParseRequestURI(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an http or https URL")
+	}
+
+	return nil
+}
+
+func (c *HTTPChecker) NewConfig() Config {
+	return &HTTPConfig{}
+}
+
+func (c *HTTPChecker) Check(ctx context.Context, monitor *store.Monitor, cfg Config) Result {
+	start := time.Now()
+	config := cfg.(*HTTPConfig)
+
+	accepted, err := parseStatusCodes(config.AcceptedStatusCodes)
+	if err != nil {
+		return down(start, err)
+	}
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	method := monitor.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if config.Body != "" {
+		body = strings.NewReader(config.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, monitor.Address, body)
+	if err != nil {
+		return down(start, err)
+	}
+
+	for key, value := range config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	// Go only honours Host through the request field, not the header map.
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	maxRedirects := defaultMaxRedirects
+	if config.MaxRedirects != nil {
+		maxRedirects = *config.MaxRedirects
+	}
+
+	client := &http.Client{
+		Transport: c.transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return down(start, err)
+	}
+	defer res.Body.Close()
+
+	var resBody []byte
+	if len(config.Assertions) > 0 {
+		resBody, err = io.ReadAll(io.LimitReader(res.Body, maxAssertedBodySize))
+		if err != nil {
+			return down(start, err)
+		}
+	}
+
+	// Drain the body so the connection can be reused by the next check.
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainedBodySize))
+
+	if !statusCodeAccepted(res.StatusCode, accepted) {
+		return down(start, fmt.Errorf("unexpected status code %d", res.StatusCode))
+	}
+
+	result := up(start)
+
+	if err := checkAssertions(resBody, config.Assertions); err != nil {
+		result.Status = StatusDown
+		result.Err = err
+		return result
+	}
+
+	if config.Certificate != nil && res.TLS != nil {
+		// The certificate of the final response is inspected, which after
+		// redirects may belong to a different host than the address.
+		result = inspectCertificate(result, monitor.ID, *res.TLS, res.Request.URL.Hostname(), nil, *config.Certificate)
+	}
+
+	return result
+}
+
+func parseStatusCodes(codes []string) ([]statusCodeRange, error) {
+	if len(codes) == 0 {
+		codes = defaultAcceptedStatusCodes
+	}
+
+	ranges := make([]statusCodeRange, 0, len(codes))
+	for _, code := range codes {
+		fromStr, toStr, isRange := strings.Cut(strings.TrimSpace(code), "-")
+		if !isRange {
+			toStr = fromStr
+		}
+
+		from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+
+		to, err := strconv.Atoi(strings.TrimSpace(toStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+
+		if from < 100 || to > 599 || from > to {
+			return nil, errors.New("status codes must be between 100 and 599 with ranges in ascending order")
+		}
+
+		ranges = append(ranges, statusCodeRange{from: from, to: to})
+	}
+
+	return ranges, nil
+}
+
+func statusCodeAccepted(code int, ranges []statusCodeRange) bool {
+	for _, r := range ranges {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+
+	return false
+}
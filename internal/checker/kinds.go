@@ -0,0 +1,286 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// DefaultKind is used for monitors that do not specify a kind.
+const DefaultKind = KindHTTP
+
+// Kind describes one type of monitor: the shape of its config and how a
+// single check is performed.
+type Kind interface {
+	Name() string
+	// ValidateAddress reports why address cannot be checked by this kind.
+	ValidateAddress(address string) error
+	// NewConfig returns a pointer to a zero value of the kind's config
+	// struct, ready to be decoded into.
+	NewConfig() Config
+	Check(ctx context.Context, monitor *store.Monitor, config Config) Result
+}
+
+// Config is implemented by every kind's config struct. Validate reports
+// problems struct tags cannot express, keyed by JSON field name.
+type Config interface {
+	Validate() map[string]string
+}
+
+// forgetter is implemented by kinds that keep state about monitors between
+// checks.
+type forgetter interface {
+	Forget(monitorID string)
+}
+
+// timeoutConfig is implemented by the configs of kinds whose checks can be
+// given a timeout of their own.
+type timeoutConfig interface {
+	timeout() time.Duration
+}
+
+// ValidationError reports which fields of a monitor were rejected, keyed by
+// their JSON path.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s %s", key, e.Fields[key]))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type Registry struct {
+	kinds    map[string]Kind
+	validate *validator.Validate
+}
+
+func NewRegistry(kinds ...Kind) *Registry {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	r := &Registry{
+		kinds:    make(map[string]Kind, len(kinds)),
+		validate: validate,
+	}
+
+	for _, kind := range kinds {
+		r.kinds[kind.Name()] = kind
+	}
+
+	return r
+}
+
+func (r *Registry) Lookup(name string) (Kind, bool) {
+	if name == "" {
+		name = DefaultKind
+	}
+
+	kind, ok := r.kinds[name]
+	return kind, ok
+}
+
+// Names returns the registered kind names in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.kinds))
+	for name := range r.kinds {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// DecodeConfig strictly decodes raw into the config struct of the named kind
+// and validates it. Problems are reported as a *ValidationError.
+func (r *Registry) DecodeConfig(name string, raw json.RawMessage) (Config, error) {
+	kind, ok := r.Lookup(name)
+	if !ok {
+		return nil, &ValidationError{Fields: map[string]string{
+			"kind": fmt.Sprintf("must be one of %s", strings.Join(r.Names(), ", ")),
+		}}
+	}
+
+	config := kind.NewConfig()
+
+	if len(raw) > 0 && string(raw) != "null" {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(config); err != nil {
+			return nil, &ValidationError{Fields: decodeErrorFields(err)}
+		}
+
+		if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+			return nil, &ValidationError{Fields: map[string]string{
+				"config": "must contain a single JSON object",
+			}}
+		}
+	}
+
+	fields := map[string]string{}
+
+	if err := r.validate.Struct(config); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return nil, err
+		}
+
+		for _, fe := range validationErrors {
+			// Drop the struct name so keys read like JSON paths.
+			_, path, _ := strings.Cut(fe.Namespace(), ".")
+			fields["config."+path] = describeFieldError(fe)
+		}
+	}
+
+	for field, msg := range config.Validate() {
+		fields["config."+field] = msg
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	return config, nil
+}
+
+// Validate checks that monitor has a registered kind and that its address
+// and config are valid for that kind. Problems are reported as a
+// *ValidationError.
+func (r *Registry) Validate(monitor *store.Monitor) error {
+	kind, ok := r.Lookup(monitor.Kind)
+	if !ok {
+		return &ValidationError{Fields: map[string]string{
+			"kind": fmt.Sprintf("must be one of %s", strings.Join(r.Names(), ", ")),
+		}}
+	}
+
+	fields := map[string]string{}
+
+	if err := kind.ValidateAddress(monitor.Address); err != nil {
+		fields["address"] = err.Error()
+	}
+
+	if _, err := r.DecodeConfig(kind.Name(), monitor.Config); err != nil {
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+
+		for field, msg := range validationErr.Fields {
+			fields[field] = msg
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+
+	return nil
+}
+
+// Check runs a single check of monitor using the kind it is configured with.
+func (r *Registry) Check(ctx context.Context, monitor *store.Monitor) Result {
+	kind, ok := r.Lookup(monitor.Kind)
+	if !ok {
+		return Result{Status: StatusDown, Err: fmt.Errorf("unknown monitor kind %q", monitor.Kind)}
+	}
+
+	config, err := r.DecodeConfig(kind.Name(), monitor.Config)
+	if err != nil {
+		return Result{Status: StatusDown, Err: fmt.Errorf("invalid config: %w", err)}
+	}
+
+	return kind.Check(ctx, monitor, config)
+}
+
+// Timeout returns the timeout configured for the checks of monitor, or 0
+// when its kind has none or its config does not set one.
+func (r *Registry) Timeout(monitor *store.Monitor) time.Duration {
+	kind, ok := r.Lookup(monitor.Kind)
+	if !ok {
+		return 0
+	}
+
+	config, err := r.DecodeConfig(kind.Name(), monitor.Config)
+	if err != nil {
+		return 0
+	}
+
+	if config, ok := config.(timeoutConfig); ok {
+		return config.timeout()
+	}
+
+	return 0
+}
+
+// Forget drops the state every kind keeps about monitorID.
+func (r *Registry) Forget(monitorID string) {
+	for _, kind := range r.kinds {
+		if kind, ok := kind.(forgetter); ok {
+			kind.Forget(monitorID)
+		}
+	}
+}
+
+func decodeErrorFields(err error) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string]string{
+			"config." + typeErr.Field: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return map[string]string{
+			"config." + strings.Trim(field, `"`): "is not a known field",
+		}
+	}
+
+	return map[string]string{"config": err.Error()}
+}
+
+func describeFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
@@ -0,0 +1,35 @@
+package checker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+func TestRegistryTimeout(t *testing.T) {
+	registry := NewRegistry(NewHTTPChecker(), NewDNSChecker(), NewPushChecker())
+
+	tests := []struct {
+		kind   string
+		config string
+		want   time.Duration
+	}{
+		{KindHTTP, `{"timeout": 120}`, 120 * time.Second},
+		{"", `{"timeout": 45}`, 45 * time.Second},
+		{KindHTTP, ``, 0},
+		{KindDNS, `{"record_type": "A", "timeout": 300}`, 300 * time.Second},
+		{KindPush, `{"grace_period": 60}`, 0},
+		{KindHTTP, `{"timeout": 301}`, 0},
+		{"unknown", `{"timeout": 10}`, 0},
+	}
+
+	for _, test := range tests {
+		monitor := &store.Monitor{Kind: test.kind, Config: json.RawMessage(test.config)}
+
+		if got := registry.Timeout(monitor); got != test.want {
+			t.Errorf("Timeout(%q, %s) = %s, want %s", test.kind, test.config, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const KindPush = "push"
+
+// PushConfig is the config of push monitors, which are not polled but
+// receive heartbeats from the monitored job instead.
+type PushConfig struct {
+	// GracePeriod in seconds is added to the interval before a missing
+	// heartbeat counts as down.
+	GracePeriod int `json:"grace_period,omitempty" validate:"gte=0,lte=86400"`
+}
+
+func (c *PushConfig) Validate() map[string]string {
+	return nil
+}
+
+// PushChecker watches for heartbeats that did not arrive in time. Its checks
+// report nothing while heartbeats are on time, since each heartbeat is
+// recorded as a result of its own.
+type PushChecker struct {
+	mu         sync.Mutex
+	heartbeats map[string]time.Time
+	started    time.Time
+}
+
+func NewPushChecker() *PushChecker {
+	return &PushChecker{
+		heartbeats: make(map[string]time.Time),
+		started:    time.Now(),
+	}
+}
+
+func (c *PushChecker) Name() string {
+	return KindPush
+}
+
+// ValidateAddress accepts anything, as push monitors are never dialed and
+// their address is purely descriptive.
+func (c *PushChecker) ValidateAddress(address string) error {
+	return nil
+}
+
+func (c *PushChecker) NewConfig() Config {
+	return &PushConfig{}
+}
+
+// Heartbeat records that monitorID reported in at the given time.
+func (c *PushChecker) Heartbeat(monitorID string, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if at.After(c.heartbeats[monitorID]) {
+		c.heartbeats[monitorID] = at
+	}
+}
+
+// Forget drops the last heartbeat of monitorID, so that it gets a fresh
+// window should it become a push monitor again.
+func (c *PushChecker) Forget(monitorID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.heartbeats, monitorID)
+}
+
+func (c *PushChecker) Check(ctx context.Context, monitor *store.Monitor, cfg Config) Result {
+	config := cfg.(*PushConfig)
+
+	c.mu.Lock()
+	last, ok := c.heartbeats[monitor.ID]
+	c.mu.Unlock()
+
+	if !ok {
+		// Heartbeats from before a restart are not known, so give the job a
+		// full window counted from when we started listening.
+		last = c.started
+		if createdAt, err := time.Parse(time.RFC3339, monitor.CreatedAt); err == nil && createdAt.After(last) {
+			last = createdAt
+		}
+	}
+
+	window := time.Duration(monitor.Interval+config.GracePeriod) * time.Second
+	if time.Since(last) <= window {
+		return Result{}
+	}
+
+	return Result{
+		Status: StatusDown,
+		Err:    fmt.Errorf("no heartbeat received since %s", last.UTC().Format(time.RFC3339)),
+	}
+}
@@ -0,0 +1,26 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+func TestPushCheckerForget(t *testing.T) {
+	push := NewPushChecker()
+	monitor := &store.Monitor{ID: "monitor", Kind: KindPush, Interval: 60}
+
+	push.Heartbeat(monitor.ID, time.Now().Add(-time.Hour))
+
+	if result := push.Check(context.Background(), monitor, &PushConfig{}); result.Status != StatusDown {
+		t.Fatalf("status with a stale heartbeat = %q, want %q", result.Status, StatusDown)
+	}
+
+	push.Forget(monitor.ID)
+
+	if result := push.Check(context.Background(), monitor, &PushConfig{}); result.Status != "" {
+		t.Fatalf("status after Forget = %q (error %v), want a fresh window", result.Status, result.Err)
+	}
+}
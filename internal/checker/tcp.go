@@ -0,0 +1,145 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const KindTCP = "tcp"
+
+// maxBannerSize bounds how much of the server's reply is read when matching
+// Expect.
+const maxBannerSize = 4096
+
+// TCPConfig is the config of TCP monitors, whose address is host:port.
+type TCPConfig struct {
+	// Send is written to the connection once it is established.
+	Send string `json:"send,omitempty"`
+	// Expect is a regular expression the banner, or the reply to Send, has
+	// to match.
+	Expect string `json:"expect,omitempty"`
+	// Timeout in seconds.
+	Timeout int `json:"timeout,omitempty" validate:"gte=0,lte=300"`
+}
+
+func (c *TCPConfig) Validate() map[string]string {
+	if c.Expect != "" {
+		if _, err := regexp.Compile(c.Expect); err != nil {
+			return map[string]string{"expect": "must be a valid regular expression"}
+		}
+	}
+
+	return nil
+}
+
+func (c *TCPConfig) timeout() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
+
+type TCPChecker struct {
+	dialer *net.Dialer
+}
+
+func NewTCPChecker() *TCPChecker {
+	return &TCPChecker{
+		dialer: &net.Dialer{},
+	}
+}
+
+func (c *TCPChecker) Name() string {
+	return KindTCP
+}
+
+func (c *TCPChecker) ValidateAddress(address string) error {
+	return validateHostPort(address)
+}
+
+func (c *TCPChecker) NewConfig() Config {
+	return &TCPConfig{}
+}
+
+func (c *TCPChecker) Check(ctx context.Context, monitor *store.Monitor, cfg Config) Result {
+	start := time.Now()
+	config := cfg.(*TCPConfig)
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", monitor.Address)
+	if err != nil {
+		return down(start, err)
+	}
+	defer conn.Close()
+
+	// Only the connect latency is reported; exchanging data below is part
+	// of the check but not of the measurement.
+	result := up(start)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if config.Send != "" {
+		if _, err := conn.Write([]byte(config.Send)); err != nil {
+			return Result{Status: StatusDown, ResponseTime: result.ResponseTime, Err: err}
+		}
+	}
+
+	if config.Expect != "" {
+		expect, err := regexp.Compile(config.Expect)
+		if err != nil {
+			return Result{Status: StatusDown, ResponseTime: result.ResponseTime, Err: err}
+		}
+
+		if err := readUntilMatch(conn, expect); err != nil {
+			return Result{Status: StatusDown, ResponseTime: result.ResponseTime, Err: err}
+		}
+	}
+
+	return result
+}
+
+// readUntilMatch reads from conn until what was received matches expect, the
+// peer closes the connection, or maxBannerSize bytes have been read.
+func readUntilMatch(conn net.Conn, expect *regexp.Regexp) error {
+	buf := make([]byte, 0, maxBannerSize)
+
+	for len(buf) < maxBannerSize {
+		n, err := conn.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+
+		if expect.Match(buf) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("response did not match %q: %w", expect, err)
+		}
+	}
+
+	return fmt.Errorf("response did not match %q", expect)
+}
+
+func validateHostPort(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return errors.New("must be in host:port form")
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return errors.New("must have a port between 1 and 65535")
+	}
+
+	return nil
+}
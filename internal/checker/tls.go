@@ -0,0 +1,222 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const KindTLS = "tls"
+
+const (
+	defaultWarningDays  = 14
+	defaultCriticalDays = 7
+)
+
+// CertificateThresholds decide when an expiring certificate turns a monitor
+// degraded or down. Zero values fall back to 14 and 7 days.
+type CertificateThresholds struct {
+	WarningDays  int `json:"warning_days,omitempty" validate:"gte=0,lte=365"`
+	CriticalDays int `json:"critical_days,omitempty" validate:"gte=0,lte=365"`
+}
+
+func (t CertificateThresholds) validate() map[string]string {
+	warning, critical := t.days()
+	if critical > warning {
+		return map[string]string{"critical_days": "must not exceed warning_days"}
+	}
+
+	return nil
+}
+
+func (t CertificateThresholds) days() (warning, critical int) {
+	warning, critical = t.WarningDays, t.CriticalDays
+	if warning == 0 {
+		warning = defaultWarningDays
+	}
+	if critical == 0 {
+		critical = defaultCriticalDays
+	}
+
+	return warning, critical
+}
+
+// TLSConfig is the config of TLS monitors, whose address is host:port.
+type TLSConfig struct {
+	WarningDays  int `json:"warning_days,omitempty" validate:"gte=0,lte=365"`
+	CriticalDays int `json:"critical_days,omitempty" validate:"gte=0,lte=365"`
+	// ServerName overrides the name sent through SNI and matched against the
+	// certificate. It defaults to the host of the address.
+	ServerName string `json:"server_name,omitempty"`
+	// Timeout in seconds.
+	Timeout int `json:"timeout,omitempty" validate:"gte=0,lte=300"`
+}
+
+func (c *TLSConfig) Validate() map[string]string {
+	return c.thresholds().validate()
+}
+
+func (c *TLSConfig) timeout() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
+
+func (c *TLSConfig) thresholds() CertificateThresholds {
+	return CertificateThresholds{WarningDays: c.WarningDays, CriticalDays: c.CriticalDays}
+}
+
+type TLSChecker struct {
+	dialer *net.Dialer
+	// roots verifies chains; nil means the system pool.
+	roots *x509.CertPool
+}
+
+func NewTLSChecker() *TLSChecker {
+	return &TLSChecker{
+		dialer: &net.Dialer{},
+	}
+}
+
+func (c *TLSChecker) Name() string {
+	return KindTLS
+}
+
+func (c *TLSChecker) ValidateAddress(address string) error {
+	return validateHostPort(address)
+}
+
+func (c *TLSChecker) NewConfig() Config {
+	return &TLSConfig{}
+}
+
+func (c *TLSChecker) Check(ctx context.Context, monitor *store.Monitor, cfg Config) Result {
+	start := time.Now()
+	config := cfg.(*TLSConfig)
+
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	serverName := config.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(monitor.Address)
+		if err != nil {
+			return down(start, err)
+		}
+		serverName = host
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: c.dialer,
+		Config: &tls.Config{
+			ServerName: serverName,
+			// The chain is verified below, so that an invalid certificate
+			// can still be inspected and recorded.
+			InsecureSkipVerify: true,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", monitor.Address)
+	if err != nil {
+		return down(start, err)
+	}
+	defer conn.Close()
+
+	result := up(start)
+	state := conn.(*tls.Conn).ConnectionState()
+
+	return inspectCertificate(result, monitor.ID, state, serverName, c.roots, config.thresholds())
+}
+
+// inspectCertificate records the leaf certificate of state on result and
+// lowers result's status when the chain is invalid, the name does not match,
+// or expiry is closer than the thresholds allow.
+func inspectCertificate(result Result, monitorID string, state tls.ConnectionState, serverName string, roots *x509.CertPool, thresholds CertificateThresholds) Result {
+	if len(state.PeerCertificates) == 0 {
+		result.Status = StatusDown
+		result.Err = errors.New("peer presented no certificate")
+		return result
+	}
+
+	now := time.Now()
+	leaf := state.PeerCertificates[0]
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	certificate := &store.Certificate{
+		MonitorID:       monitorID,
+		Subject:         leaf.Subject.String(),
+		Issuer:          leaf.Issuer.String(),
+		SANs:            certificateSANs(leaf),
+		NotBefore:       leaf.NotBefore,
+		NotAfter:        leaf.NotAfter,
+		DaysUntilExpiry: int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24)),
+		HostnameMatch:   leaf.VerifyHostname(serverName) == nil,
+		CheckedAt:       now,
+	}
+
+	// Hostname is checked separately above, so verify the chain alone.
+	_, chainErr := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	certificate.ChainValid = chainErr == nil
+
+	result.Certificate = certificate
+
+	warning, critical := thresholds.days()
+
+	var problem error
+	status := StatusUp
+
+	switch {
+	case chainErr != nil:
+		status, problem = StatusDown, fmt.Errorf("invalid certificate chain: %w", chainErr)
+	case !certificate.HostnameMatch:
+		status, problem = StatusDown, fmt.Errorf("certificate is not valid for %s", serverName)
+	case certificate.DaysUntilExpiry < critical:
+		status, problem = StatusDown, fmt.Errorf("certificate expires in %d days", certificate.DaysUntilExpiry)
+	case certificate.DaysUntilExpiry < warning:
+		status, problem = StatusDegraded, fmt.Errorf("certificate expires in %d days", certificate.DaysUntilExpiry)
+	}
+
+	if problem != nil {
+		certificate.Error = problem.Error()
+	}
+
+	// Never report a better status than the rest of the check found.
+	if statusRank(status) > statusRank(result.Status) {
+		result.Status = status
+		result.Err = problem
+	}
+
+	return result
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
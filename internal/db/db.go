@@ -3,17 +3,34 @@ package db
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
 	"time"
 
 	_ "github.com/tursodatabase/go-libsql"
 )
 
 func New(addr string, maxOpenConns, maxIdleConns int, maxIdleTime string) (*sql.DB, error) {
-	db, err := sql.Open("libsql", addr)
+	libsql, err := sql.Open("libsql", addr)
 	if err != nil {
 		return nil, err
 	}
 
+	driverContext, ok := libsql.Driver().(driver.DriverContext)
+	if !ok {
+		return nil, fmt.Errorf("the libsql driver does not open connectors")
+	}
+	connector, err := driverContext.OpenConnector(addr)
+	if err != nil {
+		return nil, err
+	}
+	// sql.Open only checked the address; the connections come from the
+	// connector below.
+	libsql.Close()
+
+	db := sql.OpenDB(&foreignKeysConnector{Connector: connector})
+
 	db.SetMaxOpenConns(maxOpenConns)
 	db.SetMaxIdleConns(maxIdleConns)
 
@@ -32,3 +49,38 @@ func New(addr string, maxOpenConns, maxIdleConns int, maxIdleTime string) (*sql.
 
 	return db, nil
 }
+
+// foreignKeysConnector enforces foreign keys on every connection it opens.
+// SQLite keeps the setting per connection, and the ON DELETE cascades of the
+// schema only run while it is on.
+type foreignKeysConnector struct {
+	driver.Connector
+}
+
+func (c *foreignKeysConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("the libsql connection does not execute statements")
+	}
+	if _, err := execer.ExecContext(ctx, "PRAGMA foreign_keys = ON", nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+	}
+
+	return conn, nil
+}
+
+// Close closes the database the connector opened.
+func (c *foreignKeysConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const (
+	// RecordPrefix is prepended to a hostname to name the TXT record proving
+	// control of it.
+	RecordPrefix = "_uptime-ume."
+	// ValuePrefix starts the text of the TXT record, followed by the token.
+	ValuePrefix = "uptime-ume-verification="
+
+	lookupTimeout = 10 * time.Second
+)
+
+var (
+	ErrNoHostname  = errors.New("the status page has no hostname")
+	ErrNotVerified = errors.New("the verification record was not found")
+)
+
+// Record is the TXT record the owner of a domain creates to serve a status
+// page on it.
+type Record struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Verifier checks that whoever set the hostname of a status page controls
+// the domain, by looking for the token stored with the page in its TXT
+// record.
+type Verifier struct {
+	resolver *net.Resolver
+}
+
+// NewVerifier returns a Verifier asking the DNS server at resolverAddr, or the
+// system resolver when it is empty.
+func NewVerifier(resolverAddr string) *Verifier {
+	resolver := net.DefaultResolver
+	if resolverAddr != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, resolverAddr)
+			},
+		}
+	}
+
+	return &Verifier{resolver: resolver}
+}
+
+// Record returns the TXT record that verifies the hostname of statusPage.
+func (v *Verifier) Record(statusPage *store.StatusPage) Record {
+	return Record{
+		Type:  "TXT",
+		Name:  RecordPrefix + statusPage.Hostname,
+		Value: ValuePrefix + statusPage.HostnameToken,
+	}
+}
+
+// Verify looks up the TXT record of statusPage's hostname. It returns
+// ErrNotVerified when the record does not hold the expected value.
+func (v *Verifier) Verify(ctx context.Context, statusPage *store.StatusPage) error {
+	if statusPage.Hostname == "" || statusPage.HostnameToken == "" {
+		return ErrNoHostname
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	record := v.Record(statusPage)
+
+	values, err := v.resolver.LookupTXT(ctx, record.Name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrNotVerified
+		}
+		return fmt.Errorf("failed to look up %s: %w", record.Name, err)
+	}
+
+	if !slices.ContainsFunc(values, func(value string) bool {
+		return strings.TrimSpace(value) == record.Value
+	}) {
+		return ErrNotVerified
+	}
+
+	return nil
+}
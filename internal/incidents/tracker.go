@@ -0,0 +1,187 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/marekh19/uptime-ume/internal/changefeed"
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// Listener is told when a monitor goes down or recovers, with the incident
+// that was opened or resolved.
+type Listener interface {
+	IncidentChanged(*store.Incident)
+}
+
+// Tracker is the monitor state machine. It opens an incident when a monitor
+// is confirmed down and resolves it once the monitor recovers.
+type Tracker struct {
+	store    store.Storage
+	feed     *changefeed.Feed
+	listener Listener
+
+	mu sync.Mutex
+	// monitors holds the state of each monitor seen so far. Each has a lock
+	// of its own, so that results of one monitor do not wait on the
+	// database calls made for another.
+	monitors map[string]*monitorState
+}
+
+type monitorState struct {
+	mu sync.Mutex
+	// loaded is set once the open incident has been read from the database.
+	loaded bool
+	// incident is the open incident of the monitor, nil when it has none.
+	incident *store.Incident
+}
+
+func NewTracker(storage store.Storage, feed *changefeed.Feed, listener Listener) *Tracker {
+	return &Tracker{
+		store:    storage,
+		feed:     feed,
+		listener: listener,
+		monitors: make(map[string]*monitorState),
+	}
+}
+
+// Run forgets monitors as they are deleted, until ctx is cancelled.
+func (t *Tracker) Run(ctx context.Context) error {
+	cursor := t.feed.Cursor()
+
+	for {
+		sub := t.feed.Subscribe(ctx, cursor)
+		for event := range sub.Events() {
+			if event.Type == changefeed.MonitorDeleted {
+				t.forget(event.MonitorID)
+			}
+			cursor = event.Cursor
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Deletions may have been dropped, so start over from the database.
+		t.mu.Lock()
+		clear(t.monitors)
+		t.mu.Unlock()
+
+		cursor = t.feed.Cursor()
+	}
+}
+
+// Observe advances the state of the result's monitor. Pending retries are
+// ignored, as they are not confirmed yet.
+func (t *Tracker) Observe(ctx context.Context, result *store.PingResult) error {
+	if result.Status == checker.StatusPending {
+		return nil
+	}
+
+	state := t.monitor(result.MonitorID)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if !state.loaded {
+		incident, err := t.store.Incidents.GetOpenByMonitorID(ctx, result.MonitorID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+
+		state.incident = incident
+		state.loaded = true
+	}
+
+	incident := state.incident
+
+	switch {
+	case result.Status == checker.StatusDown && incident == nil:
+		id, err := gonanoid.New()
+		if err != nil {
+			return err
+		}
+
+		incident = &store.Incident{
+			ID:         id,
+			MonitorID:  result.MonitorID,
+			Status:     store.IncidentOpen,
+			StartedAt:  result.Timestamp,
+			FirstError: result.Message,
+			LastError:  result.Message,
+		}
+
+		if err := t.store.Incidents.Create(ctx, incident); err != nil {
+			// Such as when the monitor has been deleted meanwhile, which
+			// leaves nothing worth keeping.
+			t.forget(result.MonitorID)
+			return err
+		}
+
+		state.incident = incident
+		t.listener.IncidentChanged(incident)
+
+	case result.Status == checker.StatusDown:
+		if result.Message == incident.LastError {
+			return nil
+		}
+
+		incident.LastError = result.Message
+
+		return t.update(ctx, state, incident)
+
+	case incident != nil:
+		resolvedAt := result.Timestamp
+		duration := int64(resolvedAt.Sub(incident.StartedAt).Seconds())
+
+		incident.Status = store.IncidentResolved
+		incident.ResolvedAt = &resolvedAt
+		incident.Duration = &duration
+
+		if err := t.update(ctx, state, incident); err != nil {
+			return err
+		}
+
+		state.incident = nil
+		t.listener.IncidentChanged(incident)
+	}
+
+	return nil
+}
+
+// monitor returns the state of monitorID, adding it when it is not known.
+func (t *Tracker) monitor(monitorID string) *monitorState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.monitors[monitorID]
+	if !ok {
+		state = &monitorState{}
+		t.monitors[monitorID] = state
+	}
+
+	return state
+}
+
+func (t *Tracker) forget(monitorID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.monitors, monitorID)
+}
+
+func (t *Tracker) update(ctx context.Context, state *monitorState, incident *store.Incident) error {
+	err := t.store.Incidents.Update(ctx, incident)
+	if errors.Is(err, store.ErrNotFound) {
+		// The incident was deleted with its monitor, which the foreign key
+		// cascades to, so there is nothing left to update.
+		state.incident = nil
+		state.loaded = false
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// Mail is a plain text email.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+	// Unsubscribe is offered to mail clients in the List-Unsubscribe
+	// header when set.
+	Unsubscribe string
+}
+
+type Mailer interface {
+	Send(context.Context, *Mail) error
+}
+
+// SMTPConfig configures the SMTP server that mail is sent through. Username
+// and Password are only used when the server supports authentication.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// SMTPMailer sends mail through an SMTP server, upgrading the connection
+// with STARTTLS when the server offers it.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail *Mail) error {
+	host, _, err := net.SplitHostPort(m.config.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address: %w", err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.config.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(sendTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if ok, _ := client.Extension("AUTH"); ok && m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(mail.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(m.message(mail)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) message(mail *Mail) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	if mail.Unsubscribe != "" {
+		fmt.Fprintf(&b, "List-Unsubscribe: <%s>\r\n", mail.Unsubscribe)
+		b.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.Write(bytes.ReplaceAll([]byte(mail.Body), []byte("\n"), []byte("\r\n")))
+
+	return b.Bytes()
+}
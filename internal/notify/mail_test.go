@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// received is a message accepted by smtpSink.
+type received struct {
+	from string
+	to   []string
+	msg  *mail.Message
+	body string
+}
+
+// smtpSink is an SMTP server that accepts every message, without STARTTLS
+// or authentication, and hands it to the test.
+type smtpSink struct {
+	addr     string
+	messages chan received
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	sink := &smtpSink{addr: l.Addr().String(), messages: make(chan received, 16)}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+
+	return sink
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 sink ESMTP\r\n")
+
+	var current received
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimSpace(line)
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			fmt.Fprint(conn, "250-sink\r\n250 8BITMIME\r\n")
+		case "MAIL":
+			current = received{from: address(command)}
+			fmt.Fprint(conn, "250 OK\r\n")
+		case "RCPT":
+			current.to = append(current.to, address(command))
+			fmt.Fprint(conn, "250 OK\r\n")
+		case "DATA":
+			fmt.Fprint(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+
+			msg, err := mail.ReadMessage(strings.NewReader(data.String()))
+			if err != nil {
+				fmt.Fprint(conn, "554 Malformed message\r\n")
+				continue
+			}
+			body, _ := io.ReadAll(msg.Body)
+
+			current.msg = msg
+			current.body = string(body)
+			s.messages <- current
+
+			fmt.Fprint(conn, "250 OK\r\n")
+		case "QUIT":
+			fmt.Fprint(conn, "221 Bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 OK\r\n")
+		}
+	}
+}
+
+// address returns the address between angle brackets in an SMTP command.
+func address(command string) string {
+	start, end := strings.Index(command, "<"), strings.LastIndex(command, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return command[start+1 : end]
+}
+
+// next waits for the next message the sink accepts.
+func (s *smtpSink) next(t *testing.T) received {
+	t.Helper()
+
+	select {
+	case message := <-s.messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an email")
+		return received{}
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	sink := newSMTPSink(t)
+
+	mailer := NewSMTPMailer(SMTPConfig{Addr: sink.addr, From: "status@example.com"})
+
+	err := mailer.Send(context.Background(), &Mail{
+		To:          "visitor@example.com",
+		Subject:     "[Example] API is down",
+		Body:        "API went down.\n\nUnsubscribe: https://status.example.com/u",
+		Unsubscribe: "https://status.example.com/u",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := sink.next(t)
+
+	if got.from != "status@example.com" {
+		t.Errorf("MAIL FROM = %q, want %q", got.from, "status@example.com")
+	}
+	if len(got.to) != 1 || got.to[0] != "visitor@example.com" {
+		t.Errorf("RCPT TO = %q, want [visitor@example.com]", got.to)
+	}
+
+	headers := map[string]string{
+		"From":                  "status@example.com",
+		"To":                    "visitor@example.com",
+		"Subject":               "[Example] API is down",
+		"List-Unsubscribe":      "<https://status.example.com/u>",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		"Content-Type":          "text/plain; charset=utf-8",
+	}
+	for name, want := range headers {
+		if got := got.msg.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	if want := "API went down.\r\n\r\nUnsubscribe: https://status.example.com/u\r\n"; got.body != want {
+		t.Errorf("body = %q, want %q", got.body, want)
+	}
+}
+
+func TestSMTPMailerSendUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	mailer := NewSMTPMailer(SMTPConfig{Addr: addr, From: "status@example.com"})
+
+	if err := mailer.Send(context.Background(), &Mail{To: "visitor@example.com"}); err == nil {
+		t.Fatal("Send to a closed port succeeded")
+	}
+}
@@ -0,0 +1,393 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/signer"
+	"github.com/marekh19/uptime-ume/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	MonitorDown   = "monitor.down"
+	MonitorUp     = "monitor.up"
+	PostPublished = "post.published"
+	PostUpdated   = "post.updated"
+)
+
+const (
+	// QueueSize is how many events, and how many confirmation emails, may
+	// wait to be delivered. Those queued while the queue is full are dropped.
+	QueueSize = 256
+	// sendTimeout bounds the delivery of one email or webhook.
+	sendTimeout = 10 * time.Second
+)
+
+// Event is what subscribers are told about. Like the public status page, it
+// names monitors but leaves out their errors.
+type Event struct {
+	Type    string        `json:"type"`
+	At      time.Time     `json:"at"`
+	Monitor *MonitorEvent `json:"monitor,omitempty"`
+	Post    *PostEvent    `json:"post,omitempty"`
+
+	// monitorID or statusPageID pick the status pages whose subscribers
+	// are notified.
+	monitorID    string
+	statusPageID string
+}
+
+type MonitorEvent struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Duration is how many seconds a recovered monitor was down.
+	Duration *int64 `json:"duration,omitempty"`
+}
+
+type PostEvent struct {
+	Kind     string     `json:"kind"`
+	Title    string     `json:"title"`
+	Severity string     `json:"severity,omitempty"`
+	Status   string     `json:"status"`
+	Message  string     `json:"message"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// webhookPayload is the body posted to webhook subscribers.
+type webhookPayload struct {
+	Event          Event       `json:"event"`
+	StatusPage     webhookPage `json:"status_page"`
+	UnsubscribeURL string      `json:"unsubscribe_url"`
+}
+
+type webhookPage struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	URL  string `json:"url"`
+}
+
+// confirmation is the link to confirm their address, waiting to be sent to
+// an email subscriber.
+type confirmation struct {
+	statusPage *store.StatusPage
+	subscriber *store.Subscriber
+}
+
+// Dispatcher notifies the subscribers of status pages about monitors going
+// down or recovering, and about posts. Events and confirmation emails are
+// queued and delivered in the background by Run, so that publishers and
+// visitors subscribing are never held up by slow mail servers or webhooks.
+type Dispatcher struct {
+	store   store.Storage
+	mailer  Mailer
+	client  *http.Client
+	signer  *signer.Signer
+	baseURL string
+	events  chan Event
+	logger  *zap.SugaredLogger
+
+	confirmations chan confirmation
+}
+
+// NewDispatcher returns a dispatcher that links to pages and subscriptions
+// under baseURL, the public address of the server.
+func NewDispatcher(storage store.Storage, mailer Mailer, signer *signer.Signer, baseURL string, logger *zap.SugaredLogger) *Dispatcher {
+	return &Dispatcher{
+		store:   storage,
+		mailer:  mailer,
+		client:  newWebhookClient(),
+		signer:  signer,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		events:  make(chan Event, QueueSize),
+		logger:  logger,
+
+		confirmations: make(chan confirmation, QueueSize),
+	}
+}
+
+// IncidentChanged publishes that the incident's monitor went down or
+// recovered.
+func (d *Dispatcher) IncidentChanged(incident *store.Incident) {
+	event := Event{
+		Type:      MonitorDown,
+		At:        incident.StartedAt,
+		Monitor:   &MonitorEvent{Status: checker.StatusDown},
+		monitorID: incident.MonitorID,
+	}
+
+	if incident.Status == store.IncidentResolved {
+		event.Type = MonitorUp
+		event.At = *incident.ResolvedAt
+		event.Monitor = &MonitorEvent{Status: checker.StatusUp, Duration: incident.Duration}
+	}
+
+	d.publish(event)
+}
+
+// PostPublished publishes a new post with its first update.
+func (d *Dispatcher) PostPublished(post *store.Post) {
+	d.publish(postEvent(PostPublished, post, post.Updates[0]))
+}
+
+// PostUpdated publishes an update added to the timeline of post.
+func (d *Dispatcher) PostUpdated(post *store.Post, update *store.PostUpdate) {
+	d.publish(postEvent(PostUpdated, post, update))
+}
+
+func postEvent(eventType string, post *store.Post, update *store.PostUpdate) Event {
+	return Event{
+		Type: eventType,
+		At:   update.CreatedAt,
+		Post: &PostEvent{
+			Kind:     post.Kind,
+			Title:    post.Title,
+			Severity: post.Severity,
+			Status:   update.Status,
+			Message:  update.Message,
+			StartsAt: post.StartsAt,
+			EndsAt:   post.EndsAt,
+		},
+		statusPageID: post.StatusPageID,
+	}
+}
+
+func (d *Dispatcher) publish(event Event) {
+	select {
+	case d.events <- event:
+	default:
+		d.logger.Warnw("Notification queue is full, dropping event", "type", event.Type)
+	}
+}
+
+// Run delivers published events and sends queued confirmations until ctx is
+// cancelled.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-d.events:
+			if err := d.deliver(ctx, event); err != nil && ctx.Err() == nil {
+				d.logger.Errorw("Failed to deliver notifications", "type", event.Type, "error", err.Error())
+			}
+		case c := <-d.confirmations:
+			if err := d.sendConfirmation(ctx, c); err != nil && ctx.Err() == nil {
+				d.logger.Warnw("Failed to send confirmation", "subscriber", c.subscriber.ID, "error", err.Error())
+			}
+		}
+	}
+}
+
+// deliver sends event to the confirmed subscribers of the status pages it
+// concerns. Failing subscribers are logged and skipped.
+func (d *Dispatcher) deliver(ctx context.Context, event Event) error {
+	var statusPages []*store.StatusPage
+
+	if event.monitorID != "" {
+		monitor, err := d.store.Monitors.GetByID(ctx, event.monitorID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		event.Monitor.Name = monitor.Name
+
+		if statusPages, err = d.store.StatusPages.ListByMonitorID(ctx, event.monitorID); err != nil {
+			return err
+		}
+	} else {
+		statusPage, err := d.store.StatusPages.GetByID(ctx, event.statusPageID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		statusPages = append(statusPages, statusPage)
+	}
+
+	for _, statusPage := range statusPages {
+		subscribers, err := d.store.Subscribers.List(ctx, store.SubscriberFilter{
+			StatusPageID: statusPage.ID,
+			Confirmed:    true,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, subscriber := range subscribers {
+			if err := d.send(ctx, event, statusPage, subscriber); err != nil {
+				d.logger.Warnw("Failed to notify subscriber",
+					"subscriber", subscriber.ID,
+					"kind", subscriber.Kind,
+					"type", event.Type,
+					"error", err.Error(),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, event Event, statusPage *store.StatusPage, subscriber *store.Subscriber) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	unsubscribe := d.UnsubscribeURL(subscriber)
+
+	switch subscriber.Kind {
+	case store.SubscriberEmail:
+		subject, body := message(event)
+
+		return d.mailer.Send(ctx, &Mail{
+			To:      subscriber.Target,
+			Subject: fmt.Sprintf("[%s] %s", statusPage.Name, subject),
+			Body: fmt.Sprintf("%s\n\nView the status page: %s\n\nUnsubscribe: %s\n",
+				body, d.StatusPageURL(statusPage), unsubscribe),
+			Unsubscribe: unsubscribe,
+		})
+
+	case store.SubscriberWebhook:
+		body, err := json.Marshal(webhookPayload{
+			Event: event,
+			StatusPage: webhookPage{
+				Name: statusPage.Name,
+				Slug: statusPage.Slug,
+				URL:  d.StatusPageURL(statusPage),
+			},
+			UnsubscribeURL: unsubscribe,
+		})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscriber.Target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "uptime-ume")
+
+		res, err := d.client.Do(req)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return fmt.Errorf("webhook responded with %s", res.Status)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown subscriber kind %q", subscriber.Kind)
+	}
+}
+
+// message returns the subject and body of an email about event.
+func message(event Event) (string, string) {
+	switch event.Type {
+	case MonitorDown:
+		return fmt.Sprintf("%s is down", event.Monitor.Name),
+			fmt.Sprintf("%s went down at %s.", event.Monitor.Name, formatTime(event.At))
+
+	case MonitorUp:
+		body := fmt.Sprintf("%s recovered at %s", event.Monitor.Name, formatTime(event.At))
+		if event.Monitor.Duration != nil {
+			body += fmt.Sprintf(" after %s", time.Duration(*event.Monitor.Duration)*time.Second)
+		}
+		return fmt.Sprintf("%s has recovered", event.Monitor.Name), body + "."
+
+	default:
+		post := event.Post
+		status := strings.ReplaceAll(post.Status, "_", " ")
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s\n\n", post.Message)
+		if post.Kind == store.PostMaintenance && post.EndsAt != nil {
+			fmt.Fprintf(&b, "Scheduled from %s to %s.\n", formatTime(post.StartsAt), formatTime(*post.EndsAt))
+		}
+		fmt.Fprintf(&b, "Status: %s", status)
+
+		return fmt.Sprintf("%s (%s)", post.Title, status), b.String()
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("Jan 2, 2006 15:04 UTC")
+}
+
+// SendConfirmation queues the email asking subscriber to confirm their
+// address. Confirmations queued while the queue is full are dropped.
+func (d *Dispatcher) SendConfirmation(statusPage *store.StatusPage, subscriber *store.Subscriber) {
+	select {
+	case d.confirmations <- confirmation{statusPage: statusPage, subscriber: subscriber}:
+	default:
+		d.logger.Warnw("Confirmation queue is full, dropping confirmation", "subscriber", subscriber.ID)
+	}
+}
+
+func (d *Dispatcher) sendConfirmation(ctx context.Context, c confirmation) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	statusPage, subscriber := c.statusPage, c.subscriber
+
+	return d.mailer.Send(ctx, &Mail{
+		To:      subscriber.Target,
+		Subject: fmt.Sprintf("[%s] Confirm your subscription", statusPage.Name),
+		Body: fmt.Sprintf("Someone, hopefully you, asked to be notified about %s.\n\n"+
+			"Confirm your subscription: %s\n\n"+
+			"If it was not you, ignore this email and you will not hear from us again.\n",
+			d.StatusPageURL(statusPage), d.ConfirmURL(subscriber)),
+	})
+}
+
+// StatusPageURL returns the address of statusPage, which is the root of its
+// custom domain once verified. The domain is assumed to be reached with the
+// same scheme as the server.
+func (d *Dispatcher) StatusPageURL(statusPage *store.StatusPage) string {
+	if statusPage.HostnameVerified() {
+		scheme := "https"
+		if base, err := url.Parse(d.baseURL); err == nil && base.Scheme != "" {
+			scheme = base.Scheme
+		}
+		return fmt.Sprintf("%s://%s", scheme, statusPage.Hostname)
+	}
+
+	return fmt.Sprintf("%s/status/%s", d.baseURL, url.PathEscape(statusPage.Slug))
+}
+
+// ConfirmURL returns the signed link that confirms an email subscriber.
+func (d *Dispatcher) ConfirmURL(subscriber *store.Subscriber) string {
+	return d.subscriptionURL(subscriber, "confirm")
+}
+
+// UnsubscribeURL returns the signed link that removes a subscriber.
+func (d *Dispatcher) UnsubscribeURL(subscriber *store.Subscriber) string {
+	return d.subscriptionURL(subscriber, "unsubscribe")
+}
+
+func (d *Dispatcher) subscriptionURL(subscriber *store.Subscriber, action string) string {
+	return fmt.Sprintf("%s/subscriptions/%s/%s?token=%s",
+		d.baseURL, url.PathEscape(subscriber.ID), action, d.signer.Sign(action, subscriber.ID))
+}
+
+// Verify reports whether token is the signature of the link for action,
+// confirm or unsubscribe, of subscriber.
+func (d *Dispatcher) Verify(subscriber *store.Subscriber, action, token string) bool {
+	return d.signer.Verify(token, action, subscriber.ID)
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/signer"
+	"github.com/marekh19/uptime-ume/internal/store"
+	"go.uber.org/zap"
+)
+
+const baseURL = "https://uptime.example.com"
+
+var linkPattern = regexp.MustCompile(`https://uptime\.example\.com/subscriptions/\S+`)
+
+func newTestDispatcher(t *testing.T, sink *smtpSink) *Dispatcher {
+	t.Helper()
+
+	mailer := NewSMTPMailer(SMTPConfig{Addr: sink.addr, From: "status@example.com"})
+
+	return NewDispatcher(store.Storage{}, mailer, signer.New([]byte("secret")), baseURL, zap.NewNop().Sugar())
+}
+
+// run runs d until the test ends.
+func run(t *testing.T, d *Dispatcher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// linkToken checks that link is the action link of subscriber and returns
+// its token.
+func linkToken(t *testing.T, link string, subscriber *store.Subscriber, action string) string {
+	t.Helper()
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse link %q: %v", link, err)
+	}
+
+	if want := "/subscriptions/" + subscriber.ID + "/" + action; u.Path != want {
+		t.Fatalf("link path = %q, want %q", u.Path, want)
+	}
+
+	return u.Query().Get("token")
+}
+
+func TestDispatcherConfirmation(t *testing.T) {
+	sink := newSMTPSink(t)
+	d := newTestDispatcher(t, sink)
+	run(t, d)
+
+	statusPage := &store.StatusPage{ID: "page", Name: "Example", Slug: "example"}
+	subscriber := &store.Subscriber{ID: "sub", StatusPageID: "page", Kind: store.SubscriberEmail, Target: "visitor@example.com"}
+
+	d.SendConfirmation(statusPage, subscriber)
+
+	got := sink.next(t)
+
+	if len(got.to) != 1 || got.to[0] != subscriber.Target {
+		t.Errorf("RCPT TO = %q, want [%s]", got.to, subscriber.Target)
+	}
+	if subject := got.msg.Header.Get("Subject"); subject != "[Example] Confirm your subscription" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if header := got.msg.Header.Get("List-Unsubscribe"); header != "" {
+		t.Errorf("List-Unsubscribe = %q, want none before confirming", header)
+	}
+
+	links := linkPattern.FindAllString(got.body, -1)
+	if len(links) != 1 {
+		t.Fatalf("found links %q, want only the confirm link", links)
+	}
+
+	token := linkToken(t, links[0], subscriber, "confirm")
+
+	if !d.Verify(subscriber, "confirm", token) {
+		t.Error("confirm token from the email is rejected")
+	}
+	if d.Verify(subscriber, "unsubscribe", token) {
+		t.Error("confirm token is accepted on the unsubscribe link")
+	}
+	if d.Verify(&store.Subscriber{ID: "other"}, "confirm", token) {
+		t.Error("confirm token is accepted for another subscriber")
+	}
+}
+
+func TestDispatcherUnsubscribe(t *testing.T) {
+	sink := newSMTPSink(t)
+	d := newTestDispatcher(t, sink)
+
+	statusPage := &store.StatusPage{ID: "page", Name: "Example", Slug: "example"}
+	subscriber := &store.Subscriber{ID: "sub", StatusPageID: "page", Kind: store.SubscriberEmail, Target: "visitor@example.com"}
+
+	post := &store.Post{
+		StatusPageID: "page",
+		Kind:         store.PostIncident,
+		Title:        "Degraded API",
+		StartsAt:     time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Updates:      []*store.PostUpdate{{Status: "investigating", Message: "Looking into it."}},
+	}
+	event := postEvent(PostPublished, post, post.Updates[0])
+
+	if err := d.send(context.Background(), event, statusPage, subscriber); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	got := sink.next(t)
+
+	if subject := got.msg.Header.Get("Subject"); subject != "[Example] Degraded API (investigating)" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	header := got.msg.Header.Get("List-Unsubscribe")
+	if !strings.HasPrefix(header, "<") || !strings.HasSuffix(header, ">") {
+		t.Fatalf("List-Unsubscribe = %q", header)
+	}
+	link := header[1 : len(header)-1]
+
+	if links := linkPattern.FindAllString(got.body, -1); len(links) != 1 || links[0] != link {
+		t.Errorf("links in body = %q, want the List-Unsubscribe link %q", links, link)
+	}
+
+	token := linkToken(t, link, subscriber, "unsubscribe")
+
+	if !d.Verify(subscriber, "unsubscribe", token) {
+		t.Error("unsubscribe token from the email is rejected")
+	}
+	if d.Verify(subscriber, "confirm", token) {
+		t.Error("unsubscribe token is accepted on the confirm link")
+	}
+	if d.Verify(&store.Subscriber{ID: "other"}, "unsubscribe", token) {
+		t.Error("unsubscribe token is accepted for another subscriber")
+	}
+}
+
+func TestWebhookRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook reached a loopback address")
+	}))
+	defer server.Close()
+
+	_, err := newWebhookClient().Post(server.URL, "application/json", strings.NewReader("{}"))
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Post to %s: got %v, want %v", server.URL, err, ErrForbiddenAddress)
+	}
+}
+
+func TestRefuseInternal(t *testing.T) {
+	tests := []struct {
+		address string
+		refused bool
+	}{
+		{"127.0.0.1:80", true},
+		{"[::1]:443", true},
+		{"10.1.2.3:80", true},
+		{"172.16.0.1:80", true},
+		{"192.168.1.1:80", true},
+		{"169.254.169.254:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd00::1]:80", true},
+		{"0.0.0.0:80", true},
+		{"0.1.2.3:80", true},
+		{"100.64.0.1:80", true},
+		{"100.100.100.200:80", true},
+		{"100.127.255.254:80", true},
+		{"[::ffff:100.100.100.200]:80", true},
+		{"100.63.255.255:80", false},
+		{"100.128.0.1:80", false},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"93.184.215.14:443", false},
+		{"[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:443", false},
+	}
+
+	for _, test := range tests {
+		err := refuseInternal("tcp", test.address, nil)
+		if refused := errors.Is(err, ErrForbiddenAddress); refused != test.refused {
+			t.Errorf("refuseInternal(%q) = %v, want refused %v", test.address, err, test.refused)
+		}
+	}
+}
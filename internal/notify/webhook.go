@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("webhooks may not be sent to loopback, link-local or private addresses")
+
+// internalPrefixes are the ranges not publicly routable that the netip.Addr
+// methods do not cover.
+var internalPrefixes = []netip.Prefix{
+	// "This network", of which IsUnspecified only covers 0.0.0.0.
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// Carrier-grade NAT, where some clouds serve instance metadata.
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// newWebhookClient returns the client webhooks are posted with. Its
+// addresses are checked as they are dialed, after name resolution and on
+// every redirect, so that subscribers cannot point webhooks at the server
+// itself or at the network it runs in.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: sendTimeout,
+		Control: refuseInternal,
+	}
+
+	return &http.Client{
+		Timeout: sendTimeout,
+		// No proxy, as it would be dialed instead of the webhook.
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: sendTimeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// refuseInternal is a net.Dialer Control function that fails connections to
+// addresses that are not publicly routable.
+func refuseInternal(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+		}
+	}
+
+	return nil
+}
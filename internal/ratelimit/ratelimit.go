@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often keys whose bucket has filled up again are
+// forgotten.
+const sweepInterval = time.Minute
+
+// Limiter allows bursts of up to burst events per key, after which events
+// are allowed again at one per interval. Each key has a token bucket of its
+// own, such as a client address or the ID of a status page.
+type Limiter struct {
+	burst    int
+	interval time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	nextSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	// updated is when tokens was last refilled.
+	updated time.Time
+}
+
+func New(burst int, interval time.Duration) *Limiter {
+	return &Limiter{
+		burst:    burst,
+		interval: interval,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of key and reports whether there was
+// one. When there was not, it also returns how long until there is.
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.After(l.nextSweep) {
+		l.sweep(now)
+		l.nextSweep = now.Add(sweepInterval)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(l.interval))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + float64(now.Sub(b.updated))/float64(l.interval)
+	return min(tokens, float64(l.burst))
+}
+
+// sweep forgets the keys that are back to a full bucket, which behave the
+// same as keys never seen.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package results
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/incidents"
+	"github.com/marekh19/uptime-ume/internal/store"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"go.uber.org/zap"
+)
+
+var ErrClosed = errors.New("recorder is closed")
+
+const (
+	// minRetryDelay and maxRetryDelay bound the backoff between attempts to
+	// write a batch the database refused.
+	minRetryDelay = time.Second
+	maxRetryDelay = 30 * time.Second
+)
+
+// dropped counts the results that could not be written before shutdown.
+var dropped = expvar.NewInt("results_dropped")
+
+// BatchConfig controls how results are buffered before they are written.
+type BatchConfig struct {
+	// Size is the number of results that triggers a write.
+	Size int
+	// Interval is the longest a result waits for a write.
+	Interval time.Duration
+	// Capacity is the number of results buffered before Record blocks.
+	Capacity int
+}
+
+// Recorder is the single path check results take into the database, whether
+// they come from the scheduler or from push heartbeats. Results are buffered
+// and written in batches, one transaction per batch, so that a busy
+// scheduler does not saturate the database with single-row inserts. Once a
+// batch is stored, incidents are brought in step with its results.
+type Recorder struct {
+	store     store.Storage
+	incidents *incidents.Tracker
+	config    BatchConfig
+	logger    *zap.SugaredLogger
+
+	queue chan *store.PingResult
+	// closing is closed with the queue, cutting short the wait before a
+	// retry.
+	closing chan struct{}
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewRecorder returns a Recorder that writes in the background until it is
+// closed.
+func NewRecorder(storage store.Storage, incidents *incidents.Tracker, config BatchConfig, logger *zap.SugaredLogger) *Recorder {
+	if config.Size < 1 {
+		config.Size = 1
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+
+	r := &Recorder{
+		store:     storage,
+		incidents: incidents,
+		config:    config,
+		logger:    logger,
+		queue:     make(chan *store.PingResult, config.Capacity),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+// Record queues result to be stored, assigning it an ID if it has none. When
+// the buffer is full it blocks until there is room or ctx is done.
+func (r *Recorder) Record(ctx context.Context, result *store.PingResult) error {
+	if result.ID == "" {
+		id, err := gonanoid.New()
+		if err != nil {
+			return err
+		}
+		result.ID = id
+	}
+
+	// Results are stored with second precision.
+	result.Timestamp = result.Timestamp.UTC().Truncate(time.Second)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	select {
+	case r.queue <- result:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting results and waits until the buffered ones are
+// written or ctx is done.
+func (r *Recorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+		close(r.closing)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	batch := make([]*store.PingResult, 0, r.config.Size)
+
+	for {
+		select {
+		case result, ok := <-r.queue:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+
+			batch = append(batch, result)
+			if len(batch) < r.config.Size {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		r.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+func (r *Recorder) flush(batch []*store.PingResult) {
+	if len(batch) == 0 {
+		return
+	}
+
+	// The batch is written even while shutting down, so it does not share a
+	// context with anything that may be cancelled.
+	ctx := context.Background()
+
+	// Failed writes are retried with backoff, holding up the queue behind
+	// them, until the recorder is closed. Then each batch gets one more
+	// attempt before it is dropped.
+	delay := minRetryDelay
+	for {
+		err := r.store.PingResults.CreateBatch(ctx, batch)
+		if err == nil {
+			break
+		}
+
+		select {
+		case <-r.closing:
+			dropped.Add(int64(len(batch)))
+			r.logger.Errorw("Dropped ping results", "count", len(batch), "error", err.Error())
+			return
+		default:
+		}
+
+		r.logger.Warnw("Failed to store ping results, retrying", "count", len(batch), "retry_in", delay.String(), "error", err.Error())
+
+		select {
+		case <-time.After(delay):
+		case <-r.closing:
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+
+	// The results themselves are stored; a failure to track them only
+	// affects incidents, so it is logged rather than returned.
+	for _, result := range batch {
+		if err := r.incidents.Observe(ctx, result); err != nil {
+			r.logger.Errorw("Failed to track incident", "monitor", result.MonitorID, "error", err.Error())
+		}
+	}
+}
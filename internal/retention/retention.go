@@ -0,0 +1,214 @@
+package retention
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// Interval is how often the pruner looks for expired results.
+	Interval = time.Hour
+	// BatchSize caps how many rows a single delete removes, so that no delete
+	// holds the database's write lock for long.
+	BatchSize = 1000
+	// batchPause lets other writers in between two batches.
+	batchPause = 10 * time.Millisecond
+)
+
+// raw names raw ping results next to the rollup resolutions.
+const raw = "raw"
+
+// pruned counts the rows deleted so far, keyed by raw or rollup resolution.
+var pruned = expvar.NewMap("retention_pruned")
+
+// Policy says how many days results are kept, raw and per rollup
+// resolution. 0 keeps them forever.
+type Policy struct {
+	RawDays    int
+	MinuteDays int
+	HourDays   int
+	DayDays    int
+}
+
+// For returns the policy with the overrides of a monitor applied.
+func (p Policy) For(override *store.Retention) Policy {
+	if override == nil {
+		return p
+	}
+
+	apply := func(days *int, override *int) {
+		if override != nil {
+			*days = *override
+		}
+	}
+
+	apply(&p.RawDays, override.RawDays)
+	apply(&p.MinuteDays, override.MinuteDays)
+	apply(&p.HourDays, override.HourDays)
+	apply(&p.DayDays, override.DayDays)
+
+	return p
+}
+
+func (p Policy) days(resolution string) int {
+	switch resolution {
+	case raw:
+		return p.RawDays
+	case store.ResolutionMinute:
+		return p.MinuteDays
+	case store.ResolutionHour:
+		return p.HourDays
+	case store.ResolutionDay:
+		return p.DayDays
+	default:
+		return 0
+	}
+}
+
+// Pruner periodically deletes results that outlived the retention policy of
+// their monitor. Results are never deleted before they have been rolled up
+// into the next coarser resolution.
+type Pruner struct {
+	store  store.Storage
+	policy Policy
+	logger *zap.SugaredLogger
+}
+
+func NewPruner(storage store.Storage, policy Policy, logger *zap.SugaredLogger) *Pruner {
+	return &Pruner{
+		store:  storage,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// Run prunes results every Interval until ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Prune(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			p.logger.Errorw("Failed to prune results", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the results that expired by now.
+func (p *Pruner) Prune(ctx context.Context, now time.Time) error {
+	watermarks, err := p.store.Rollups.Watermarks(ctx)
+	if err != nil {
+		return err
+	}
+
+	monitors, err := p.store.Monitors.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	totals := make(map[string]int64)
+	defer func() {
+		for resolution, total := range totals {
+			pruned.Add(resolution, total)
+		}
+
+		if len(totals) > 0 {
+			p.logger.Infow("Pruned expired results", "raw", totals[raw], "minute", totals[store.ResolutionMinute], "hour", totals[store.ResolutionHour], "day", totals[store.ResolutionDay])
+		}
+	}()
+
+	// Each level may only be pruned once it has been rolled up into the next
+	// one: raw results into minutes, minutes into hours and hours into days.
+	levels := append([]string{raw}, resolutionNames()...)
+
+	for _, monitor := range monitors {
+		policy := p.policy.For(monitor.Retention)
+
+		for i, level := range levels {
+			days := policy.days(level)
+			if days == 0 {
+				continue
+			}
+
+			before := now.UTC().AddDate(0, 0, -days)
+			if i+1 < len(levels) {
+				if rolledUntil := watermarks[levels[i+1]]; rolledUntil.Before(before) {
+					before = rolledUntil
+				}
+			}
+			if before.IsZero() {
+				continue
+			}
+
+			deleted, err := p.deleteBatches(ctx, func() (int64, error) {
+				if level == raw {
+					return p.store.PingResults.DeleteBefore(ctx, monitor.ID, before, BatchSize)
+				}
+				return p.store.Rollups.DeleteBefore(ctx, level, monitor.ID, before, BatchSize)
+			})
+			if deleted > 0 {
+				totals[level] += deleted
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Results of monitors that no longer exist have no policy of their own
+	// and are deleted whatever their age.
+	for _, level := range levels {
+		deleted, err := p.deleteBatches(ctx, func() (int64, error) {
+			if level == raw {
+				return p.store.PingResults.DeleteOrphans(ctx, BatchSize)
+			}
+			return p.store.Rollups.DeleteOrphans(ctx, level, BatchSize)
+		})
+		if deleted > 0 {
+			totals[level] += deleted
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBatches calls deleteBatch until it deletes less than a full batch.
+func (p *Pruner) deleteBatches(ctx context.Context, deleteBatch func() (int64, error)) (int64, error) {
+	var total int64
+
+	for {
+		deleted, err := deleteBatch()
+		total += deleted
+		if err != nil || deleted < BatchSize {
+			return total, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, ctx.Err()
+		case <-time.After(batchPause):
+		}
+	}
+}
+
+func resolutionNames() []string {
+	names := make([]string, len(stats.Resolutions))
+	for i, resolution := range stats.Resolutions {
+		names[i] = resolution.Name
+	}
+	return names
+}
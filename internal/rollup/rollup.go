@@ -0,0 +1,176 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// Interval is how often the job looks for buckets to roll up.
+	Interval = time.Minute
+	// MinLag is the least the job waits after a bucket ends before rolling
+	// it up, so that results written late still land in it.
+	MinLag = time.Minute
+	// batchBuckets caps how many buckets of one resolution are rolled up in
+	// a single transaction.
+	batchBuckets = 10
+)
+
+// Job periodically aggregates raw ping results into per-minute buckets, and
+// those into per-hour and per-day buckets. It remembers how far each
+// resolution has been rolled up, so that it picks up where it left off.
+type Job struct {
+	store  store.Storage
+	lag    time.Duration
+	logger *zap.SugaredLogger
+}
+
+// New returns a Job for results that the recorder buffers for up to
+// flushInterval. A result is timestamped when its check starts, so it can
+// reach the database as late as the longest check, the flush interval and
+// the insert after its timestamp; buckets are only rolled up after that.
+func New(storage store.Storage, flushInterval time.Duration, logger *zap.SugaredLogger) *Job {
+	return &Job{
+		store:  storage,
+		lag:    max(MinLag, checker.MaxTimeout+flushInterval+store.QueryTimeoutDuration),
+		logger: logger,
+	}
+}
+
+// Run rolls up results every Interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context) error {
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RollUp(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			j.logger.Errorw("Failed to roll up ping results", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RollUp rolls up every bucket that ended at least the lag of the job before
+// now.
+func (j *Job) RollUp(ctx context.Context, now time.Time) error {
+	watermarks, err := j.store.Rollups.Watermarks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+
+	for i, resolution := range stats.Resolutions {
+		until := now.Add(-j.lag).Truncate(resolution.Size)
+		if i > 0 {
+			// Coarser buckets are built from finer ones, which must be
+			// complete first.
+			if source := watermarks[stats.Resolutions[i-1].Name].Truncate(resolution.Size); source.Before(until) {
+				until = source
+			}
+		}
+
+		from, ok := watermarks[resolution.Name]
+		if !ok {
+			oldest, err := j.store.PingResults.GetOldestTimestamp(ctx)
+			if err != nil {
+				if errors.Is(err, store.ErrNotFound) {
+					return nil
+				}
+				return err
+			}
+			from = oldest.Truncate(resolution.Size)
+		}
+
+		for from.Before(until) {
+			to := from.Add(batchBuckets * resolution.Size)
+			if to.After(until) {
+				to = until
+			}
+
+			rollups, err := j.aggregate(ctx, i, from, to)
+			if err != nil {
+				return err
+			}
+
+			if err := j.store.Rollups.Save(ctx, resolution.Name, rollups, to); err != nil {
+				return err
+			}
+
+			j.logger.Debugw("Rolled up ping results", "resolution", resolution.Name, "until", to, "buckets", len(rollups))
+
+			watermarks[resolution.Name] = to
+			from = to
+		}
+	}
+
+	return nil
+}
+
+// aggregate builds the rollups of stats.Resolutions[level] for [from, to)
+// from the level below it, or from raw results for the finest level.
+func (j *Job) aggregate(ctx context.Context, level int, from, to time.Time) ([]*store.Rollup, error) {
+	resolution := stats.Resolutions[level]
+
+	type key struct {
+		monitorID string
+		bucket    time.Time
+	}
+
+	aggregates := make(map[key]*stats.Aggregate)
+	get := func(monitorID string, t time.Time) *stats.Aggregate {
+		k := key{monitorID: monitorID, bucket: t.UTC().Truncate(resolution.Size)}
+		aggregate, ok := aggregates[k]
+		if !ok {
+			aggregate = stats.NewAggregate()
+			aggregates[k] = aggregate
+		}
+		return aggregate
+	}
+
+	if level == 0 {
+		results, err := j.store.PingResults.List(ctx, store.PingResultFilter{From: from, To: to})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			get(result.MonitorID, result.Timestamp).AddResult(result)
+		}
+	} else {
+		rollups, err := j.store.Rollups.List(ctx, store.RollupFilter{
+			Resolution: stats.Resolutions[level-1].Name,
+			From:       from,
+			To:         to,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rollup := range rollups {
+			get(rollup.MonitorID, rollup.Bucket).AddRollup(rollup)
+		}
+	}
+
+	rollups := make([]*store.Rollup, 0, len(aggregates))
+	for k, aggregate := range aggregates {
+		// Buckets holding only pending retries have nothing to report.
+		if aggregate.Checks == 0 {
+			continue
+		}
+		rollups = append(rollups, aggregate.Rollup(k.monitorID, resolution.Name, k.bucket))
+	}
+
+	return rollups, nil
+}
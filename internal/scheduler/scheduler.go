@@ -0,0 +1,336 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/changefeed"
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/results"
+	"github.com/marekh19/uptime-ume/internal/store"
+	"go.uber.org/zap"
+)
+
+// Scheduler runs every monitor on its own interval and records the outcome
+// of each check as a ping result. Monitor changes are picked up from the
+// change feed as they happen.
+type Scheduler struct {
+	store    store.Storage
+	checker  checker.Checker
+	feed     *changefeed.Feed
+	recorder *results.Recorder
+	logger   *zap.SugaredLogger
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	wg   sync.WaitGroup
+}
+
+type job struct {
+	version int
+	kind    string
+	cancel  context.CancelFunc
+	state   *confirmation
+}
+
+func New(store store.Storage, checker checker.Checker, feed *changefeed.Feed, recorder *results.Recorder, logger *zap.SugaredLogger) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		checker:  checker,
+		feed:     feed,
+		recorder: recorder,
+		logger:   logger,
+		jobs:     make(map[string]*job),
+	}
+}
+
+// Run loads all monitors and checks them until ctx is cancelled. It blocks
+// until every in-flight check has finished.
+func (s *Scheduler) Run(ctx context.Context) error {
+	defer s.wg.Wait()
+	defer s.stopAll()
+
+	cursor := s.feed.Cursor()
+	if err := s.sync(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Infow("Scheduler has started", "monitors", len(s.jobs))
+
+	for {
+		sub := s.feed.Subscribe(ctx, cursor)
+		for event := range sub.Events() {
+			s.apply(ctx, event)
+			cursor = event.Cursor
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Events were dropped before we could see them, so the running jobs
+		// may no longer match the database.
+		s.logger.Warnw("Monitor change feed fell behind, resyncing", "error", sub.Err().Error())
+
+		cursor = s.feed.Cursor()
+		if err := s.sync(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// sync makes the running jobs match the monitors currently stored.
+func (s *Scheduler) sync(ctx context.Context) error {
+	monitors, err := s.store.Monitors.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(monitors))
+	for _, monitor := range monitors {
+		seen[monitor.ID] = true
+		s.start(ctx, monitor, true)
+	}
+
+	s.mu.Lock()
+	for id, job := range s.jobs {
+		if !seen[id] {
+			job.cancel()
+			delete(s.jobs, id)
+			s.checker.Forget(id)
+		}
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Scheduler) apply(ctx context.Context, event changefeed.Event) {
+	switch event.Type {
+	case changefeed.MonitorCreated, changefeed.MonitorUpdated:
+		s.start(ctx, event.Monitor, false)
+	case changefeed.MonitorDeleted:
+		s.stop(event.MonitorID)
+	}
+}
+
+// start (re)schedules monitor unless a job for the same version is already
+// running. Staggered jobs wait a random part of their interval before the
+// first check, so monitors loaded together do not all fire at once.
+func (s *Scheduler) start(ctx context.Context, monitor *store.Monitor, staggered bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.jobs[monitor.ID]; ok {
+		if existing.version >= monitor.Version {
+			return
+		}
+		existing.cancel()
+		delete(s.jobs, monitor.ID)
+
+		// Heartbeats and the like only carry over while the monitor is
+		// checked the same way.
+		if existing.kind != monitor.Kind {
+			s.checker.Forget(monitor.ID)
+		}
+	}
+
+	if monitor.Interval <= 0 {
+		s.logger.Warnw("Skipping monitor with invalid interval", "monitor", monitor.ID, "interval", monitor.Interval)
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	state := &confirmation{}
+	s.jobs[monitor.ID] = &job{version: monitor.Version, kind: monitor.Kind, cancel: cancel, state: state}
+
+	interval := time.Duration(monitor.Interval) * time.Second
+
+	var delay time.Duration
+	if staggered {
+		delay = rand.N(interval)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(jobCtx, monitor, state, interval, delay)
+	}()
+}
+
+// stop cancels the job of a deleted monitor and forgets the monitor.
+func (s *Scheduler) stop(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.jobs[id]; ok {
+		existing.cancel()
+		delete(s.jobs, id)
+	}
+
+	s.checker.Forget(id)
+}
+
+func (s *Scheduler) stopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, job := range s.jobs {
+		job.cancel()
+		delete(s.jobs, id)
+	}
+}
+
+// confirmation tracks the retries of a failing monitor within one job. It is
+// shared by the checks of the job and the heartbeats of push monitors.
+type confirmation struct {
+	mu sync.Mutex
+	// status is the last confirmed status, empty while unknown.
+	status   string
+	failures int
+}
+
+func (s *Scheduler) loop(ctx context.Context, monitor *store.Monitor, state *confirmation, interval, delay time.Duration) {
+	retryInterval := interval
+	if monitor.RetryInterval > 0 {
+		retryInterval = time.Duration(monitor.RetryInterval) * time.Second
+	}
+
+	// Pick up where the previous job left off, so a monitor that was already
+	// down is not retried all over again after a restart or an edit.
+	latest, err := s.store.PingResults.GetLatestConfirmed(ctx, monitor.ID)
+	if err == nil {
+		state.mu.Lock()
+		if state.status == "" {
+			state.status = latest.Status
+		}
+		state.mu.Unlock()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		started := time.Now()
+
+		next := interval
+		if s.check(ctx, monitor, interval, state) == checker.StatusPending {
+			next = retryInterval
+		}
+
+		timer.Reset(max(next-time.Since(started), 0))
+	}
+}
+
+// check runs a single check of monitor and records its result, returning the
+// recorded status. The check gets the timeout the monitor configures, or
+// checker.DefaultTimeout, but never longer than its interval.
+func (s *Scheduler) check(ctx context.Context, monitor *store.Monitor, interval time.Duration, state *confirmation) string {
+	timeout := checker.DefaultTimeout
+	if configured := s.checker.Timeout(monitor); configured > 0 {
+		timeout = configured
+	}
+	timeout = min(interval, timeout)
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	timestamp := time.Now()
+	result := s.checker.Check(checkCtx, monitor)
+
+	// A check cut short because the job was stopped or replaced says
+	// nothing about the target.
+	if ctx.Err() != nil || result.Status == "" {
+		return ""
+	}
+
+	state.confirm(&result, monitor.Retries)
+
+	var message string
+	if result.Err != nil {
+		message = result.Err.Error()
+		s.logger.Debugw("Check failed", "monitor", monitor.ID, "status", result.Status, "error", message)
+	}
+
+	pingResult := &store.PingResult{
+		MonitorID:    monitor.ID,
+		Status:       result.Status,
+		Timestamp:    timestamp,
+		ResponseTime: int(result.ResponseTime.Milliseconds()),
+		Message:      message,
+	}
+
+	if err := s.recorder.Record(ctx, pingResult); err != nil {
+		s.logger.Errorw("Failed to store ping result", "monitor", monitor.ID, "error", err.Error())
+	}
+
+	if result.Certificate != nil {
+		if err := s.store.Certificates.Upsert(ctx, result.Certificate); err != nil {
+			s.logger.Errorw("Failed to store certificate", "monitor", monitor.ID, "error", err.Error())
+		}
+	}
+
+	return result.Status
+}
+
+// Report records a result a push monitor reported itself, confirming a down
+// report through the retries of the monitor like a failed check.
+func (s *Scheduler) Report(ctx context.Context, monitor *store.Monitor, pingResult *store.PingResult) error {
+	s.mu.Lock()
+	existing, ok := s.jobs[monitor.ID]
+	s.mu.Unlock()
+
+	state := &confirmation{}
+	if ok {
+		state = existing.state
+	} else if latest, err := s.store.PingResults.GetLatestConfirmed(ctx, monitor.ID); err == nil {
+		state.status = latest.Status
+	}
+
+	result := checker.Result{Status: pingResult.Status}
+	switch {
+	case pingResult.Message != "":
+		result.Err = errors.New(pingResult.Message)
+	case pingResult.Status == checker.StatusDown:
+		result.Err = errors.New("reported down")
+	}
+
+	state.confirm(&result, monitor.Retries)
+
+	pingResult.Status = result.Status
+	if result.Err != nil {
+		pingResult.Message = result.Err.Error()
+	}
+
+	return s.recorder.Record(ctx, pingResult)
+}
+
+// confirm turns a failure of a monitor that is not yet known to be down into
+// a pending result until it has failed retries more times in a row.
+func (state *confirmation) confirm(result *checker.Result, retries int) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	switch {
+	case result.Status != checker.StatusDown:
+		state.status = result.Status
+		state.failures = 0
+	case state.status == checker.StatusDown || state.failures >= retries:
+		state.status = checker.StatusDown
+		state.failures = 0
+	default:
+		state.failures++
+		result.Status = checker.StatusPending
+		result.Err = fmt.Errorf("attempt %d of %d failed: %w", state.failures, retries+1, result.Err)
+	}
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// Resolution is a rollup bucket size.
+type Resolution struct {
+	Name string
+	Size time.Duration
+}
+
+// Resolutions lists the rollup resolutions from finest to coarsest. Each one
+// is rolled up from the one before it, the finest from raw results.
+var Resolutions = []Resolution{
+	{Name: store.ResolutionMinute, Size: time.Minute},
+	{Name: store.ResolutionHour, Size: time.Hour},
+	{Name: store.ResolutionDay, Size: 24 * time.Hour},
+}
+
+// Aggregate accumulates confirmed results, either one by one or as whole
+// rollups.
+type Aggregate struct {
+	Checks          int
+	Up              int
+	Degraded        int
+	Down            int
+	ResponseTimeSum int64
+	ResponseTimes   Histogram
+}
+
+func NewAggregate() *Aggregate {
+	return &Aggregate{ResponseTimes: make(Histogram)}
+}
+
+// AddResult counts result. Pending retries are ignored.
+func (a *Aggregate) AddResult(result *store.PingResult) {
+	switch result.Status {
+	case checker.StatusUp:
+		a.Up++
+	case checker.StatusDegraded:
+		a.Degraded++
+	case checker.StatusDown:
+		a.Checks++
+		a.Down++
+		return
+	default:
+		return
+	}
+
+	a.Checks++
+	a.ResponseTimeSum += int64(result.ResponseTime)
+	a.ResponseTimes.Add(result.ResponseTime)
+}
+
+// AddSummary counts every result aggregated by summary.
+func (a *Aggregate) AddSummary(summary *store.PingResultSummary) {
+	a.Checks += summary.Checks
+	a.Up += summary.Up
+	a.Degraded += summary.Degraded
+	a.Down += summary.Down
+	a.ResponseTimeSum += summary.ResponseTimeSum
+	for ms, count := range summary.ResponseTimes {
+		a.ResponseTimes[histogramIndex(ms)] += count
+	}
+}
+
+// AddRollup counts every result aggregated by rollup.
+func (a *Aggregate) AddRollup(rollup *store.Rollup) {
+	a.Checks += rollup.Checks
+	a.Up += rollup.Up
+	a.Degraded += rollup.Degraded
+	a.Down += rollup.Down
+	a.ResponseTimeSum += rollup.ResponseTimeSum
+	a.ResponseTimes.Merge(rollup.ResponseTimes)
+}
+
+// Uptime returns the percentage of checks that were up or degraded, or nil
+// when nothing was counted.
+func (a *Aggregate) Uptime() *float64 {
+	if a.Checks == 0 {
+		return nil
+	}
+
+	uptime := float64(a.Up+a.Degraded) / float64(a.Checks) * 100
+	return &uptime
+}
+
+// ResponseTime summarises the response times counted so far.
+func (a *Aggregate) ResponseTime() ResponseTime {
+	answered := a.Up + a.Degraded
+	if answered == 0 {
+		return ResponseTime{}
+	}
+
+	return ResponseTime{
+		Avg: float64(a.ResponseTimeSum) / float64(answered),
+		P50: a.ResponseTimes.Percentile(50),
+		P95: a.ResponseTimes.Percentile(95),
+		P99: a.ResponseTimes.Percentile(99),
+	}
+}
+
+// Rollup returns the aggregate as the rollup of a monitor's bucket.
+func (a *Aggregate) Rollup(monitorID, resolution string, bucket time.Time) *store.Rollup {
+	responseTime := a.ResponseTime()
+
+	return &store.Rollup{
+		MonitorID:       monitorID,
+		Resolution:      resolution,
+		Bucket:          bucket,
+		Checks:          a.Checks,
+		Up:              a.Up,
+		Degraded:        a.Degraded,
+		Down:            a.Down,
+		ResponseTimeSum: a.ResponseTimeSum,
+		P50ResponseTime: responseTime.P50,
+		P95ResponseTime: responseTime.P95,
+		P99ResponseTime: responseTime.P99,
+		ResponseTimes:   a.ResponseTimes,
+	}
+}
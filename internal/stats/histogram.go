@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// histogramGamma is the growth factor between histogram bucket bounds. A
+// value read back from the histogram is within about 5% of the values that
+// were added to its bucket.
+const histogramGamma = 1.1
+
+var logHistogramGamma = math.Log(histogramGamma)
+
+// Histogram counts response times in milliseconds in exponentially growing
+// buckets. Unlike exact percentiles, histograms of adjacent time ranges can
+// be merged by adding them up.
+type Histogram map[int]int
+
+// Add counts a response time of ms milliseconds.
+func (h Histogram) Add(ms int) {
+	h[histogramIndex(ms)]++
+}
+
+// Merge adds the counts of other to h.
+func (h Histogram) Merge(other map[int]int) {
+	for index, count := range other {
+		h[index] += count
+	}
+}
+
+// Percentile returns the nearest-rank p-th percentile, or 0 when the
+// histogram is empty.
+func (h Histogram) Percentile(p float64) int {
+	total := 0
+	indexes := make([]int, 0, len(h))
+	for index, count := range h {
+		total += count
+		indexes = append(indexes, index)
+	}
+	if total == 0 {
+		return 0
+	}
+	sort.Ints(indexes)
+
+	rank := int(math.Ceil(p / 100 * float64(total)))
+	seen := 0
+	for _, index := range indexes {
+		seen += h[index]
+		if seen >= rank {
+			return histogramValue(index)
+		}
+	}
+
+	return histogramValue(indexes[len(indexes)-1])
+}
+
+// histogramIndex returns the bucket of ms. Bucket 0 holds zero and bucket i
+// holds values in (gamma^(i-2), gamma^(i-1)].
+func histogramIndex(ms int) int {
+	if ms <= 0 {
+		return 0
+	}
+	return int(math.Ceil(math.Log(float64(ms))/logHistogramGamma)) + 1
+}
+
+// histogramValue returns the value that represents bucket index.
+func histogramValue(index int) int {
+	if index <= 0 {
+		return 0
+	}
+	upper := math.Pow(histogramGamma, float64(index-1))
+	return int(math.Round(2 * upper / (histogramGamma + 1)))
+}
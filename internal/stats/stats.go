@@ -0,0 +1,351 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// Window is a named time range that statistics are computed over.
+type Window struct {
+	Name     string
+	Duration time.Duration
+}
+
+// StandardWindows are reported when no custom range is requested.
+var StandardWindows = []Window{
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+}
+
+// LookupWindow returns the standard window called name.
+func LookupWindow(name string) (Window, bool) {
+	for _, window := range StandardWindows {
+		if window.Name == name {
+			return window, true
+		}
+	}
+	return Window{}, false
+}
+
+// Stats describes a monitor over a time range. Pending retries are not
+// counted as checks.
+type Stats struct {
+	Window   string    `json:"window"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Checks   int       `json:"checks"`
+	Up       int       `json:"up"`
+	Degraded int       `json:"degraded"`
+	Down     int       `json:"down"`
+	// Uptime is the percentage of checks that were up or degraded, or nil
+	// when there were no checks.
+	Uptime *float64 `json:"uptime"`
+	// Downtime is the number of seconds within the range covered by
+	// incidents, outside of maintenance.
+	Downtime int64 `json:"downtime"`
+	// Maintenance is the number of seconds within the range the monitor was
+	// under scheduled maintenance. Results and incidents during maintenance
+	// are left out of all other stats.
+	Maintenance  int64        `json:"maintenance"`
+	Incidents    int          `json:"incidents"`
+	ResponseTime ResponseTime `json:"response_time"`
+}
+
+// ResponseTime summarises response times in milliseconds of the checks
+// that were up or degraded. Percentiles are read from a Histogram.
+type ResponseTime struct {
+	Avg float64 `json:"avg"`
+	P50 int     `json:"p50"`
+	P95 int     `json:"p95"`
+	P99 int     `json:"p99"`
+}
+
+type Calculator struct {
+	store store.Storage
+}
+
+func NewCalculator(storage store.Storage) *Calculator {
+	return &Calculator{store: storage}
+}
+
+// Window computes the stats of a monitor over the window ending at now.
+func (c *Calculator) Window(ctx context.Context, monitorID string, window Window, now time.Time) (*Stats, error) {
+	stats, err := c.Range(ctx, monitorID, now.Add(-window.Duration), now)
+	if err != nil {
+		return nil, err
+	}
+	stats.Window = window.Name
+
+	return stats, nil
+}
+
+// Range computes the stats of a monitor over [from, to).
+func (c *Calculator) Range(ctx context.Context, monitorID string, from, to time.Time) (*Stats, error) {
+	from, to = from.UTC().Truncate(time.Second), to.UTC().Truncate(time.Second)
+
+	stats := &Stats{Window: "custom", From: from, To: to}
+
+	watermarks, err := c.store.Rollups.Watermarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := c.store.Posts.ListMaintenanceWindows(ctx, monitorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, err := c.aggregate(ctx, monitorID, from, to, windows, watermarks)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Checks = aggregate.Checks
+	stats.Up = aggregate.Up
+	stats.Degraded = aggregate.Degraded
+	stats.Down = aggregate.Down
+	stats.Uptime = aggregate.Uptime()
+	stats.ResponseTime = aggregate.ResponseTime()
+
+	incidents, err := c.store.Incidents.List(ctx, store.IncidentFilter{
+		MonitorID: monitorID,
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+
+	ranges := outside(from, to, windows)
+
+	stats.Maintenance = int64(to.Sub(from) / time.Second)
+	for _, r := range ranges {
+		stats.Maintenance -= int64(r.end.Sub(r.start) / time.Second)
+	}
+
+	for _, incident := range incidents {
+		var downtime int64
+		counted := false
+		for _, r := range ranges {
+			if incident.StartedAt.Before(r.end) && (incident.ResolvedAt == nil || incident.ResolvedAt.After(r.start)) {
+				counted = true
+			}
+			downtime += overlap(incident, r.start, r.end)
+		}
+
+		if counted {
+			stats.Incidents++
+			stats.Downtime += downtime
+		}
+	}
+
+	return stats, nil
+}
+
+// Day is the uptime of a monitor on one UTC day.
+type Day struct {
+	Date   string `json:"date"`
+	Checks int    `json:"checks"`
+	// Uptime is nil when there were no checks that day.
+	Uptime *float64 `json:"uptime"`
+}
+
+// Daily returns the uptime of a monitor on each of the last days UTC days,
+// oldest first and ending with today up to now.
+func (c *Calculator) Daily(ctx context.Context, monitorID string, days int, now time.Time) ([]Day, error) {
+	now = now.UTC().Truncate(time.Second)
+	today := now.Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(days - 1))
+
+	watermarks, err := c.store.Rollups.Watermarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rollups, err := c.store.Rollups.List(ctx, store.RollupFilter{
+		Resolution: store.ResolutionDay,
+		MonitorID:  monitorID,
+		From:       first,
+		To:         today.Add(24 * time.Hour),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rollups: %w", err)
+	}
+
+	windows, err := c.store.Posts.ListMaintenanceWindows(ctx, monitorID, first, now)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[time.Time]*store.Rollup, len(rollups))
+	for _, rollup := range rollups {
+		byDate[rollup.Bucket.UTC()] = rollup
+	}
+
+	result := make([]Day, days)
+	for i := range result {
+		date := first.AddDate(0, 0, i)
+		end := date.Add(24 * time.Hour)
+
+		// Day rollups include results during maintenance, so days with
+		// maintenance are added up from finer data that can leave it out.
+		var aggregate *Aggregate
+		if !end.After(watermarks[store.ResolutionDay]) && !overlapsAny(date, end, windows) {
+			aggregate = NewAggregate()
+			if rollup, ok := byDate[date]; ok {
+				aggregate.AddRollup(rollup)
+			}
+		} else {
+			if end.After(now) {
+				end = now
+			}
+			if aggregate, err = c.aggregate(ctx, monitorID, date, end, windows, watermarks); err != nil {
+				return nil, err
+			}
+		}
+
+		result[i] = Day{
+			Date:   date.Format(time.DateOnly),
+			Checks: aggregate.Checks,
+			Uptime: aggregate.Uptime(),
+		}
+	}
+
+	return result, nil
+}
+
+// aggregate adds up the results of a monitor in [from, to) outside of the
+// maintenance windows, reading the coarsest rollups that fit.
+func (c *Calculator) aggregate(ctx context.Context, monitorID string, from, to time.Time, windows []store.MaintenanceWindow, watermarks map[string]time.Time) (*Aggregate, error) {
+	aggregate := NewAggregate()
+
+	// Walk the resolutions from coarsest to finest.
+	levels := make([]Resolution, len(Resolutions))
+	for i, resolution := range Resolutions {
+		levels[len(levels)-1-i] = resolution
+	}
+
+	for _, r := range outside(from, to, windows) {
+		if err := c.cover(ctx, aggregate, monitorID, r.start, r.end, levels, watermarks); err != nil {
+			return nil, err
+		}
+	}
+
+	return aggregate, nil
+}
+
+// span is a time range [start, end).
+type span struct {
+	start, end time.Time
+}
+
+// outside returns the parts of [from, to) not covered by windows, which
+// must be ordered by start.
+func outside(from, to time.Time, windows []store.MaintenanceWindow) []span {
+	var ranges []span
+
+	start := from
+	for _, window := range windows {
+		if !window.End.After(start) || !window.Start.Before(to) {
+			continue
+		}
+		if window.Start.After(start) {
+			ranges = append(ranges, span{start: start, end: window.Start})
+		}
+		start = window.End
+	}
+
+	if start.Before(to) {
+		ranges = append(ranges, span{start: start, end: to})
+	}
+
+	return ranges
+}
+
+// overlapsAny reports whether any of windows overlaps [from, to).
+func overlapsAny(from, to time.Time, windows []store.MaintenanceWindow) bool {
+	for _, window := range windows {
+		if window.Start.Before(to) && window.End.After(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// cover adds the results of a monitor in [from, to) to aggregate. The
+// buckets of levels[0] that fit in the range and have been rolled up are read
+// from rollups; the rest of the range on either side is covered by the finer
+// levels, and finally by raw results.
+func (c *Calculator) cover(ctx context.Context, aggregate *Aggregate, monitorID string, from, to time.Time, levels []Resolution, watermarks map[string]time.Time) error {
+	if !from.Before(to) {
+		return nil
+	}
+
+	if len(levels) == 0 {
+		summary, err := c.store.PingResults.Summarize(ctx, monitorID, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to summarize ping results: %w", err)
+		}
+
+		aggregate.AddSummary(summary)
+		return nil
+	}
+
+	level, finer := levels[0], levels[1:]
+
+	start := from.Truncate(level.Size)
+	if start.Before(from) {
+		start = start.Add(level.Size)
+	}
+
+	end := to
+	if watermark := watermarks[level.Name]; watermark.Before(end) {
+		end = watermark
+	}
+	end = end.Truncate(level.Size)
+
+	if !start.Before(end) {
+		return c.cover(ctx, aggregate, monitorID, from, to, finer, watermarks)
+	}
+
+	rollups, err := c.store.Rollups.List(ctx, store.RollupFilter{
+		Resolution: level.Name,
+		MonitorID:  monitorID,
+		From:       start,
+		To:         end,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list rollups: %w", err)
+	}
+
+	for _, rollup := range rollups {
+		aggregate.AddRollup(rollup)
+	}
+
+	if err := c.cover(ctx, aggregate, monitorID, from, start, finer, watermarks); err != nil {
+		return err
+	}
+
+	return c.cover(ctx, aggregate, monitorID, end, to, finer, watermarks)
+}
+
+// overlap returns how many seconds of [from, to) incident covers. Open
+// incidents last until to.
+func overlap(incident *store.Incident, from, to time.Time) int64 {
+	start, end := incident.StartedAt, to
+	if incident.ResolvedAt != nil && incident.ResolvedAt.Before(end) {
+		end = *incident.ResolvedAt
+	}
+	if start.Before(from) {
+		start = from
+	}
+	if !end.After(start) {
+		return 0
+	}
+
+	return int64(end.Sub(start) / time.Second)
+}
@@ -0,0 +1,190 @@
+package statuspage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+// StatusMaintenance is shown for components an operator has put under
+// maintenance.
+const StatusMaintenance = "maintenance"
+
+type GroupView struct {
+	Name       string           `json:"name"`
+	Status     string           `json:"status"`
+	Components []*ComponentView `json:"components"`
+}
+
+type ComponentView struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status"`
+	// Overridden is true while an operator has set the status, explained
+	// by Message.
+	Overridden bool   `json:"overridden"`
+	Message    string `json:"message,omitempty"`
+	// Uptime and Days are rolled up from the monitors like the status.
+	Uptime *float64    `json:"uptime"`
+	Days   []stats.Day `json:"days"`
+}
+
+// components adds the components of page to view, backed by the monitors
+// already in monitors by ID, and bases the status of the page on them.
+func (b *Builder) components(ctx context.Context, page *store.StatusPage, view *View, monitors map[string]*MonitorView, now time.Time) error {
+	components, err := b.store.Components.List(ctx, page.ID)
+	if err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+
+	groups, err := b.store.ComponentGroups.List(ctx, page.ID)
+	if err != nil {
+		return err
+	}
+
+	byGroup := make(map[string]*GroupView, len(groups))
+	for _, group := range groups {
+		groupView := &GroupView{
+			Name:       group.Name,
+			Status:     StatusUnknown,
+			Components: []*ComponentView{},
+		}
+		view.Groups = append(view.Groups, groupView)
+		byGroup[group.ID] = groupView
+	}
+
+	view.Status = StatusUnknown
+
+	for _, component := range components {
+		componentView := newComponentView(component, monitors, now)
+		view.Status = Worst(view.Status, componentView.Status)
+
+		if component.GroupID == nil || byGroup[*component.GroupID] == nil {
+			view.Components = append(view.Components, componentView)
+			continue
+		}
+
+		groupView := byGroup[*component.GroupID]
+		groupView.Components = append(groupView.Components, componentView)
+		groupView.Status = Worst(groupView.Status, componentView.Status)
+	}
+
+	// Empty groups are left out.
+	view.Groups = slices.DeleteFunc(view.Groups, func(group *GroupView) bool {
+		return len(group.Components) == 0
+	})
+
+	return nil
+}
+
+func newComponentView(component *store.Component, monitors map[string]*MonitorView, now time.Time) *ComponentView {
+	view := &ComponentView{
+		Name:        component.Name,
+		Description: component.Description,
+		Days:        []stats.Day{},
+	}
+
+	var backing []*MonitorView
+	statuses := make(map[string]string, len(component.MonitorIDs))
+	for _, monitorID := range component.MonitorIDs {
+		if monitor, ok := monitors[monitorID]; ok {
+			backing = append(backing, monitor)
+			statuses[monitorID] = monitor.Status
+		}
+	}
+
+	view.Status = ComponentStatus(component, statuses, now)
+	if component.Override.Active(now) {
+		view.Overridden = true
+		view.Message = component.Override.Message
+	}
+
+	if len(backing) == 0 {
+		return view
+	}
+
+	view.Days = make([]stats.Day, len(backing[0].Days))
+	for i := range view.Days {
+		day := stats.Day{Date: backing[0].Days[i].Date}
+
+		var uptimes []float64
+		for _, monitor := range backing {
+			if i >= len(monitor.Days) {
+				continue
+			}
+			day.Checks += monitor.Days[i].Checks
+			if uptime := monitor.Days[i].Uptime; uptime != nil {
+				uptimes = append(uptimes, *uptime)
+			}
+		}
+
+		if len(uptimes) > 0 {
+			sort.Sort(sort.Reverse(sort.Float64Slice(uptimes)))
+			uptime := uptimes[ruleIndex(component.Rule, len(uptimes))]
+			day.Uptime = &uptime
+		}
+
+		view.Days[i] = day
+	}
+
+	view.Uptime = daysUptime(view.Days)
+
+	return view
+}
+
+// ComponentStatus returns the status shown for component: the one set by an
+// operator while it is active, or else the statuses of its monitors by ID
+// rolled up by its rule. Monitors missing from statuses are left out.
+func ComponentStatus(component *store.Component, statuses map[string]string, now time.Time) string {
+	if component.Override.Active(now) {
+		return component.Override.Status
+	}
+
+	var known []string
+	for _, monitorID := range component.MonitorIDs {
+		if status, ok := statuses[monitorID]; ok {
+			known = append(known, status)
+		}
+	}
+
+	return Rollup(component.Rule, known)
+}
+
+// Rollup combines statuses by rule. Unknown statuses are left out, unless
+// there are no others.
+func Rollup(rule string, statuses []string) string {
+	known := slices.DeleteFunc(slices.Clone(statuses), func(status string) bool {
+		return status == StatusUnknown
+	})
+	if len(known) == 0 {
+		return StatusUnknown
+	}
+
+	// Best first
+	sort.SliceStable(known, func(i, j int) bool {
+		return rank(known[i]) < rank(known[j])
+	})
+
+	return known[ruleIndex(rule, len(known))]
+}
+
+// ruleIndex returns which of n values ordered best first rule picks: the
+// best for any, the worst for all, and for majority the best value that more
+// than half of them reach.
+func ruleIndex(rule string, n int) int {
+	switch rule {
+	case store.RuleAny:
+		return 0
+	case store.RuleMajority:
+		return n / 2
+	default:
+		return n - 1
+	}
+}
@@ -0,0 +1,158 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const (
+	// FeedDays is how far back the feeds of a status page reach.
+	FeedDays = 30
+	// MaxFeedEntries caps the number of entries in a feed.
+	MaxFeedEntries = 50
+)
+
+// FeedEntry is an item of the Atom and RSS feeds of a status page: a post,
+// or a monitor going down and recovering. Entries change as their timeline
+// grows, but keep their ID.
+type FeedEntry struct {
+	// ID identifies the post or incident the entry is about, and is the
+	// same in every feed it appears in.
+	ID        string
+	Kind      string
+	Title     string
+	Published time.Time
+	Updated   time.Time
+	// Updates is the timeline of the entry, newest first.
+	Updates []PostUpdateView
+}
+
+// FeedStateChange is the kind of entries about a monitor going down and
+// recovering. Entries about posts have the kind of the post.
+const FeedStateChange = "state_change"
+
+// Feed returns the entries of the feeds of page as of now, most recently
+// updated first.
+func (b *Builder) Feed(ctx context.Context, page *store.StatusPage, now time.Time) ([]FeedEntry, error) {
+	from := now.AddDate(0, 0, -FeedDays)
+	entries := []FeedEntry{}
+
+	posts, err := b.store.Posts.List(ctx, store.PostFilter{
+		StatusPageID: page.ID,
+		From:         from,
+		Limit:        MaxFeedEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		entry := FeedEntry{
+			ID:        "post:" + post.ID,
+			Kind:      post.Kind,
+			Title:     post.Title,
+			Published: post.StartsAt,
+			Updates:   make([]PostUpdateView, 0, len(post.Updates)),
+		}
+
+		// Edits of the post count as updates too, while the start of a
+		// maintenance may still be ahead.
+		if updatedAt, err := time.Parse(time.RFC3339, post.UpdatedAt); err == nil {
+			entry.Updated = updatedAt
+		}
+
+		for _, update := range post.Updates {
+			entry.Updates = append(entry.Updates, PostUpdateView{
+				Status:    update.Status,
+				Message:   update.Message,
+				CreatedAt: update.CreatedAt,
+			})
+			if update.CreatedAt.After(entry.Updated) {
+				entry.Updated = update.CreatedAt
+			}
+		}
+
+		// Maintenance is announced before it starts.
+		if len(post.Updates) > 0 {
+			entry.Published = post.Updates[len(post.Updates)-1].CreatedAt
+		}
+
+		if entry.Updated.IsZero() {
+			entry.Updated = entry.Published
+		}
+
+		entries = append(entries, entry)
+	}
+
+	for _, monitorID := range page.MonitorIDs {
+		monitor, err := b.store.Monitors.GetByID(ctx, monitorID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		incidents, err := b.store.Incidents.List(ctx, store.IncidentFilter{
+			MonitorID: monitor.ID,
+			From:      from,
+			Limit:     MaxFeedEntries,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, incident := range incidents {
+			entries = append(entries, stateChangeEntry(monitor, incident))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Updated.After(entries[j].Updated)
+	})
+	if len(entries) > MaxFeedEntries {
+		entries = entries[:MaxFeedEntries]
+	}
+
+	return entries, nil
+}
+
+// stateChangeEntry describes an incident of monitor as it going down and,
+// once resolved, recovering.
+func stateChangeEntry(monitor *store.Monitor, incident *store.Incident) FeedEntry {
+	entry := FeedEntry{
+		ID:        "incident:" + incident.ID,
+		Kind:      FeedStateChange,
+		Title:     monitor.Name + " is down",
+		Published: incident.StartedAt,
+		Updated:   incident.StartedAt,
+		Updates: []PostUpdateView{{
+			Status:    checker.StatusDown,
+			Message:   monitor.Name + " went down.",
+			CreatedAt: incident.StartedAt,
+		}},
+	}
+
+	if incident.ResolvedAt != nil {
+		message := monitor.Name + " recovered."
+		if incident.Duration != nil {
+			message = fmt.Sprintf("%s recovered after %s.", monitor.Name, time.Duration(*incident.Duration)*time.Second)
+		}
+
+		entry.Title = monitor.Name + " was down"
+		entry.Updated = *incident.ResolvedAt
+		entry.Updates = append([]PostUpdateView{{
+			Status:    checker.StatusUp,
+			Message:   message,
+			CreatedAt: *incident.ResolvedAt,
+		}}, entry.Updates...)
+	}
+
+	return entry
+}
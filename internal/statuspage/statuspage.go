@@ -0,0 +1,326 @@
+package statuspage
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marekh19/uptime-ume/internal/checker"
+	"github.com/marekh19/uptime-ume/internal/stats"
+	"github.com/marekh19/uptime-ume/internal/store"
+)
+
+const (
+	// HistoryDays is the number of daily uptime bars shown per monitor.
+	HistoryDays = 90
+	// IncidentDays is how far back incidents are shown.
+	IncidentDays = 14
+	// MaxIncidents caps the number of incidents shown.
+	MaxIncidents = 20
+	// MaxPosts caps the number of announcements and maintenance posts shown.
+	MaxPosts = 20
+
+	// StatusUnknown is shown for monitors without any confirmed result.
+	StatusUnknown = "unknown"
+
+	// ViewTTL is how long a built view is served before it is built again,
+	// so that visitors, feeds readers and badges polling the same page do
+	// not each query its whole history.
+	ViewTTL = 15 * time.Second
+)
+
+// View is what visitors of a status page see. Monitors are shown by name
+// only, and incidents without their error messages. Pages with components
+// show those instead of their monitors, and take their status from them.
+type View struct {
+	Name     string         `json:"name"`
+	Slug     string         `json:"slug"`
+	Status   string         `json:"status"`
+	Monitors []*MonitorView `json:"monitors"`
+	// Components are the components outside of any group, shown before
+	// the groups.
+	Components []*ComponentView `json:"components"`
+	Groups     []*GroupView     `json:"groups"`
+	// Announcements are the incident posts of the last IncidentDays days and
+	// those still ongoing.
+	Announcements []PostView `json:"announcements"`
+	// Maintenance is the maintenance that is upcoming, in progress or ended
+	// within the last IncidentDays days.
+	Maintenance []PostView     `json:"maintenance"`
+	Incidents   []IncidentView `json:"incidents"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
+
+type MonitorView struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Uptime is the percentage over the days shown, nil without checks.
+	Uptime *float64    `json:"uptime"`
+	Days   []stats.Day `json:"days"`
+}
+
+type IncidentView struct {
+	Monitor    string     `json:"monitor"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	Duration   *int64     `json:"duration"`
+}
+
+type PostView struct {
+	Title    string `json:"title"`
+	Severity string `json:"severity,omitempty"`
+	Status   string `json:"status"`
+	// Monitors are the names of the affected monitors shown on the page.
+	Monitors []string         `json:"monitors"`
+	StartsAt time.Time        `json:"starts_at"`
+	EndsAt   *time.Time       `json:"ends_at"`
+	Updates  []PostUpdateView `json:"updates"`
+}
+
+type PostUpdateView struct {
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Builder assembles the public view of status pages.
+type Builder struct {
+	store store.Storage
+	stats *stats.Calculator
+
+	mu sync.Mutex
+	// views caches the latest view of each page by ID.
+	views map[string]*cachedView
+}
+
+type cachedView struct {
+	view *View
+	// updatedAt is when the page had last been edited when it was built.
+	updatedAt string
+	expiresAt time.Time
+}
+
+func NewBuilder(storage store.Storage, calculator *stats.Calculator) *Builder {
+	return &Builder{
+		store: storage,
+		stats: calculator,
+		views: make(map[string]*cachedView),
+	}
+}
+
+// Build returns the view of page as of now, or as of up to ViewTTL earlier
+// unless the page has been edited since. The view is shared between callers,
+// which must not modify it.
+func (b *Builder) Build(ctx context.Context, page *store.StatusPage, now time.Time) (*View, error) {
+	b.mu.Lock()
+	cached, ok := b.views[page.ID]
+	b.mu.Unlock()
+
+	if ok && cached.updatedAt == page.UpdatedAt && now.Before(cached.expiresAt) {
+		return cached.view, nil
+	}
+
+	view, err := b.build(ctx, page, now)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Expired views are dropped along the way, so that deleted pages do
+	// not stay cached.
+	for id, cached := range b.views {
+		if !now.Before(cached.expiresAt) {
+			delete(b.views, id)
+		}
+	}
+
+	b.views[page.ID] = &cachedView{
+		view:      view,
+		updatedAt: page.UpdatedAt,
+		expiresAt: now.Add(ViewTTL),
+	}
+
+	return view, nil
+}
+
+func (b *Builder) build(ctx context.Context, page *store.StatusPage, now time.Time) (*View, error) {
+	view := &View{
+		Name:          page.Name,
+		Slug:          page.Slug,
+		Status:        StatusUnknown,
+		Monitors:      []*MonitorView{},
+		Components:    []*ComponentView{},
+		Groups:        []*GroupView{},
+		Announcements: []PostView{},
+		Maintenance:   []PostView{},
+		Incidents:     []IncidentView{},
+		GeneratedAt:   now.UTC().Truncate(time.Second),
+	}
+
+	names := make(map[string]string, len(page.MonitorIDs))
+	monitors := make(map[string]*MonitorView, len(page.MonitorIDs))
+
+	for _, monitorID := range page.MonitorIDs {
+		monitor, err := b.store.Monitors.GetByID(ctx, monitorID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		monitorView, err := b.monitor(ctx, monitor, now)
+		if err != nil {
+			return nil, err
+		}
+		view.Monitors = append(view.Monitors, monitorView)
+		names[monitor.ID] = monitor.Name
+		monitors[monitor.ID] = monitorView
+		view.Status = Worst(view.Status, monitorView.Status)
+
+		incidents, err := b.store.Incidents.List(ctx, store.IncidentFilter{
+			MonitorID: monitor.ID,
+			From:      now.AddDate(0, 0, -IncidentDays),
+			Limit:     MaxIncidents,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, incident := range incidents {
+			view.Incidents = append(view.Incidents, IncidentView{
+				Monitor:    monitor.Name,
+				Status:     incident.Status,
+				StartedAt:  incident.StartedAt,
+				ResolvedAt: incident.ResolvedAt,
+				Duration:   incident.Duration,
+			})
+		}
+	}
+
+	if err := b.components(ctx, page, view, monitors, now); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(view.Incidents, func(i, j int) bool {
+		return view.Incidents[i].StartedAt.After(view.Incidents[j].StartedAt)
+	})
+	if len(view.Incidents) > MaxIncidents {
+		view.Incidents = view.Incidents[:MaxIncidents]
+	}
+
+	posts, err := b.store.Posts.List(ctx, store.PostFilter{
+		StatusPageID: page.ID,
+		From:         now.AddDate(0, 0, -IncidentDays),
+		Limit:        MaxPosts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		postView := PostView{
+			Title:    post.Title,
+			Severity: post.Severity,
+			Status:   post.Status,
+			Monitors: []string{},
+			StartsAt: post.StartsAt,
+			EndsAt:   post.EndsAt,
+			Updates:  make([]PostUpdateView, 0, len(post.Updates)),
+		}
+
+		for _, monitorID := range post.MonitorIDs {
+			if name, ok := names[monitorID]; ok {
+				postView.Monitors = append(postView.Monitors, name)
+			}
+		}
+
+		for _, update := range post.Updates {
+			postView.Updates = append(postView.Updates, PostUpdateView{
+				Status:    update.Status,
+				Message:   update.Message,
+				CreatedAt: update.CreatedAt,
+			})
+		}
+
+		switch post.Kind {
+		case store.PostMaintenance:
+			view.Maintenance = append(view.Maintenance, postView)
+		default:
+			view.Announcements = append(view.Announcements, postView)
+		}
+	}
+
+	return view, nil
+}
+
+func (b *Builder) monitor(ctx context.Context, monitor *store.Monitor, now time.Time) (*MonitorView, error) {
+	view := &MonitorView{
+		Name:   monitor.Name,
+		Status: StatusUnknown,
+	}
+
+	latest, err := b.store.PingResults.GetLatestConfirmed(ctx, monitor.ID)
+	switch {
+	case err == nil:
+		view.Status = latest.Status
+	case !errors.Is(err, store.ErrNotFound):
+		return nil, err
+	}
+
+	if view.Days, err = b.stats.Daily(ctx, monitor.ID, HistoryDays, now); err != nil {
+		return nil, err
+	}
+
+	view.Uptime = daysUptime(view.Days)
+
+	return view, nil
+}
+
+// daysUptime returns the uptime over days weighted by their checks, or nil
+// without checks.
+func daysUptime(days []stats.Day) *float64 {
+	var checks int
+	var up float64
+	for _, day := range days {
+		if day.Uptime != nil {
+			checks += day.Checks
+			up += *day.Uptime / 100 * float64(day.Checks)
+		}
+	}
+	if checks == 0 {
+		return nil
+	}
+
+	uptime := up / float64(checks) * 100
+	return &uptime
+}
+
+// Worst returns the worse of two statuses. Unknown only wins over unknown,
+// and maintenance is better than degraded.
+func Worst(a, b string) string {
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+func rank(status string) int {
+	switch status {
+	case StatusUnknown:
+		return 0
+	case checker.StatusUp:
+		return 1
+	case StatusMaintenance:
+		return 2
+	case checker.StatusDegraded:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Certificate is the peer certificate seen by the latest TLS check of a
+// monitor.
+type Certificate struct {
+	MonitorID       string    `json:"monitor_id"`
+	Subject         string    `json:"subject"`
+	Issuer          string    `json:"issuer"`
+	SANs            []string  `json:"sans"`
+	NotBefore       time.Time `json:"not_before"`
+	NotAfter        time.Time `json:"not_after"`
+	DaysUntilExpiry int       `json:"days_until_expiry"`
+	HostnameMatch   bool      `json:"hostname_match"`
+	ChainValid      bool      `json:"chain_valid"`
+	Error           string    `json:"error,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+}
+
+type CertificateStore struct {
+	db *sql.DB
+}
+
+func (s *CertificateStore) Upsert(ctx context.Context, certificate *Certificate) error {
+	query := `
+    INSERT INTO monitor_certificates (
+      monitor_id, subject, issuer, sans, not_before, not_after,
+      days_until_expiry, hostname_match, chain_valid, error, checked_at
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    ON CONFLICT (monitor_id) DO UPDATE SET
+      subject = excluded.subject,
+      issuer = excluded.issuer,
+      sans = excluded.sans,
+      not_before = excluded.not_before,
+      not_after = excluded.not_after,
+      days_until_expiry = excluded.days_until_expiry,
+      hostname_match = excluded.hostname_match,
+      chain_valid = excluded.chain_valid,
+      error = excluded.error,
+      checked_at = excluded.checked_at;
+  `
+
+	sans, err := json.Marshal(certificate.SANs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err = s.db.ExecContext(
+		ctx,
+		query,
+		certificate.MonitorID,
+		certificate.Subject,
+		certificate.Issuer,
+		string(sans),
+		formatTime(certificate.NotBefore),
+		formatTime(certificate.NotAfter),
+		certificate.DaysUntilExpiry,
+		certificate.HostnameMatch,
+		certificate.ChainValid,
+		certificate.Error,
+		formatTime(certificate.CheckedAt),
+	)
+
+	return err
+}
+
+func (s *CertificateStore) GetByMonitorID(ctx context.Context, monitorID string) (*Certificate, error) {
+	query := `
+    SELECT monitor_id, subject, issuer, sans, not_before, not_after,
+      days_until_expiry, hostname_match, chain_valid, error, checked_at
+    FROM monitor_certificates
+    WHERE monitor_id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var certificate Certificate
+	var sans string
+
+	err := s.db.QueryRowContext(ctx, query, monitorID).Scan(
+		&certificate.MonitorID,
+		&certificate.Subject,
+		&certificate.Issuer,
+		&sans,
+		&certificate.NotBefore,
+		&certificate.NotAfter,
+		&certificate.DaysUntilExpiry,
+		&certificate.HostnameMatch,
+		&certificate.ChainValid,
+		&certificate.Error,
+		&certificate.CheckedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal([]byte(sans), &certificate.SANs); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
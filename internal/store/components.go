@@ -0,0 +1,551 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rules rolling up the statuses of the monitors backing a component.
+const (
+	// RuleAll makes a component only as good as its worst monitor.
+	RuleAll = "all"
+	// RuleAny makes a component as good as its best monitor, for services
+	// that stay available while any of their replicas is.
+	RuleAny = "any"
+	// RuleMajority makes a component as good as the status that more than
+	// half of its monitors have or better.
+	RuleMajority = "majority"
+)
+
+// ComponentGroup is a named section of a status page. Groups are shown
+// ordered by position.
+type ComponentGroup struct {
+	ID           string `json:"id"`
+	StatusPageID string `json:"status_page_id"`
+	Name         string `json:"name"`
+	Position     int    `json:"position"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// Component is a service shown on a status page, backed by monitors whose
+// statuses are combined by Rule. Components outside of any group have no
+// GroupID and are shown before the groups.
+type Component struct {
+	ID           string             `json:"id"`
+	StatusPageID string             `json:"status_page_id"`
+	GroupID      *string            `json:"group_id"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Position     int                `json:"position"`
+	Rule         string             `json:"rule"`
+	MonitorIDs   []string           `json:"monitors"`
+	Override     *ComponentOverride `json:"override"`
+	CreatedAt    string             `json:"created_at"`
+	UpdatedAt    string             `json:"updated_at"`
+}
+
+// ComponentOverride is a status set by an operator, shown instead of the one
+// of the component's monitors until it expires or is removed.
+type ComponentOverride struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// ExpiresAt is nil for overrides that last until removed.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Active reports whether the override is in effect at now.
+func (o *ComponentOverride) Active(now time.Time) bool {
+	return o != nil && (o.ExpiresAt == nil || now.Before(*o.ExpiresAt))
+}
+
+type ComponentGroupStore struct {
+	db *sql.DB
+}
+
+const componentGroupColumns = `id, status_page_id, name, position, created_at, updated_at`
+
+func scanComponentGroup(row interface{ Scan(...any) error }, group *ComponentGroup) error {
+	return row.Scan(
+		&group.ID,
+		&group.StatusPageID,
+		&group.Name,
+		&group.Position,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+}
+
+// Create stores group after the other groups of its status page.
+func (s *ComponentGroupStore) Create(ctx context.Context, group *ComponentGroup) error {
+	query := `
+    INSERT INTO status_page_component_groups (id, status_page_id, name, position)
+    VALUES ($1, $2, $3, (
+      SELECT COALESCE(MAX(position) + 1, 0)
+      FROM status_page_component_groups
+      WHERE status_page_id = $2
+    ))
+    RETURNING position, created_at, updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.QueryRowContext(
+		ctx,
+		query,
+		group.ID,
+		group.StatusPageID,
+		group.Name,
+	).Scan(&group.Position, &group.CreatedAt, &group.UpdatedAt)
+}
+
+func (s *ComponentGroupStore) GetByID(ctx context.Context, id string) (*ComponentGroup, error) {
+	query := `
+    SELECT ` + componentGroupColumns + `
+    FROM status_page_component_groups
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var group ComponentGroup
+
+	err := scanComponentGroup(s.db.QueryRowContext(ctx, query, id), &group)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &group, nil
+}
+
+// List returns the groups of a status page in the order they are shown.
+func (s *ComponentGroupStore) List(ctx context.Context, statusPageID string) ([]*ComponentGroup, error) {
+	query := `
+    SELECT ` + componentGroupColumns + `
+    FROM status_page_component_groups
+    WHERE status_page_id = $1
+    ORDER BY position, created_at, id;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, statusPageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch component groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []*ComponentGroup{}
+	for rows.Next() {
+		var group ComponentGroup
+		if err := scanComponentGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan component group: %w", err)
+		}
+		groups = append(groups, &group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (s *ComponentGroupStore) Update(ctx context.Context, group *ComponentGroup) error {
+	query := `
+    UPDATE status_page_component_groups
+    SET name = $1, position = $2
+    WHERE id = $3
+    RETURNING updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, group.Name, group.Position, group.ID).Scan(&group.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes a group. Its components are kept outside of any group.
+func (s *ComponentGroupStore) Delete(ctx context.Context, id string) error {
+	ungroupQuery := `
+    UPDATE status_page_components
+    SET group_id = NULL
+    WHERE group_id = $1;
+  `
+	query := `
+    DELETE FROM status_page_component_groups
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Not left to ON DELETE SET NULL, which only runs with foreign keys on.
+	if _, err := tx.ExecContext(ctx, ungroupQuery, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+type ComponentStore struct {
+	db *sql.DB
+}
+
+const componentColumns = `id, status_page_id, group_id, name, description, position, rule, override_status, override_message, override_expires_at, created_at, updated_at`
+
+func scanComponent(row interface{ Scan(...any) error }, component *Component) error {
+	var groupID sql.NullString
+	var override ComponentOverride
+
+	err := row.Scan(
+		&component.ID,
+		&component.StatusPageID,
+		&groupID,
+		&component.Name,
+		&component.Description,
+		&component.Position,
+		&component.Rule,
+		&override.Status,
+		&override.Message,
+		&override.ExpiresAt,
+		&component.CreatedAt,
+		&component.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	component.GroupID = nil
+	if groupID.Valid {
+		component.GroupID = &groupID.String
+	}
+
+	component.Override = nil
+	if override.Status != "" {
+		component.Override = &override
+	}
+
+	return nil
+}
+
+// overrideColumns returns the values of the override columns of component.
+func overrideColumns(component *Component) (status, message string, expiresAt any) {
+	if component.Override == nil {
+		return "", "", nil
+	}
+
+	return component.Override.Status, component.Override.Message, nullableTime(component.Override.ExpiresAt)
+}
+
+func nullableID(id *string) any {
+	if id == nil {
+		return nil
+	}
+
+	return *id
+}
+
+// Create stores component with its monitors, after the other components of
+// its status page.
+func (s *ComponentStore) Create(ctx context.Context, component *Component) error {
+	query := `
+    INSERT INTO status_page_components (id, status_page_id, group_id, name, description, position, rule, override_status, override_message, override_expires_at)
+    VALUES ($1, $2, $3, $4, $5, (
+      SELECT COALESCE(MAX(position) + 1, 0)
+      FROM status_page_components
+      WHERE status_page_id = $2
+    ), $6, $7, $8, $9)
+    RETURNING position, created_at, updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	overrideStatus, overrideMessage, overrideExpiresAt := overrideColumns(component)
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		component.ID,
+		component.StatusPageID,
+		nullableID(component.GroupID),
+		component.Name,
+		component.Description,
+		component.Rule,
+		overrideStatus,
+		overrideMessage,
+		overrideExpiresAt,
+	).Scan(&component.Position, &component.CreatedAt, &component.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := setComponentMonitors(ctx, tx, component.ID, component.MonitorIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setComponentMonitors replaces the monitors backing a component.
+func setComponentMonitors(ctx context.Context, tx *sql.Tx, componentID string, monitorIDs []string) error {
+	query := `
+    DELETE FROM status_page_component_monitors
+    WHERE component_id = $1;
+  `
+
+	if _, err := tx.ExecContext(ctx, query, componentID); err != nil {
+		return err
+	}
+
+	if len(monitorIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(monitorIDs))
+	args := []any{componentID}
+	for i, monitorID := range monitorIDs {
+		args = append(args, monitorID)
+		values[i] = fmt.Sprintf("($1, $%d)", len(args))
+	}
+
+	query = `
+    INSERT INTO status_page_component_monitors (component_id, monitor_id)
+    VALUES ` + strings.Join(values, ", ") + `;`
+
+	_, err := tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// loadMonitorIDs fills in the monitors backing components.
+func (s *ComponentStore) loadMonitorIDs(ctx context.Context, components ...*Component) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Component, len(components))
+	placeholders := make([]string, len(components))
+	args := make([]any, len(components))
+	for i, component := range components {
+		component.MonitorIDs = []string{}
+		byID[component.ID] = component
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = component.ID
+	}
+
+	query := `
+    SELECT component_id, monitor_id
+    FROM status_page_component_monitors
+    WHERE component_id IN (` + strings.Join(placeholders, ", ") + `)
+    ORDER BY rowid;`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch component monitors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var componentID, monitorID string
+		if err := rows.Scan(&componentID, &monitorID); err != nil {
+			return fmt.Errorf("failed to scan component monitor: %w", err)
+		}
+		byID[componentID].MonitorIDs = append(byID[componentID].MonitorIDs, monitorID)
+	}
+
+	return rows.Err()
+}
+
+func (s *ComponentStore) GetByID(ctx context.Context, id string) (*Component, error) {
+	query := `
+    SELECT ` + componentColumns + `
+    FROM status_page_components
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var component Component
+
+	err := scanComponent(s.db.QueryRowContext(ctx, query, id), &component)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := s.loadMonitorIDs(ctx, &component); err != nil {
+		return nil, err
+	}
+
+	return &component, nil
+}
+
+// List returns the components of a status page in the order they are shown
+// within their groups.
+func (s *ComponentStore) List(ctx context.Context, statusPageID string) ([]*Component, error) {
+	query := `
+    SELECT ` + componentColumns + `
+    FROM status_page_components
+    WHERE status_page_id = $1
+    ORDER BY position, created_at, id;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, statusPageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch components: %w", err)
+	}
+	defer rows.Close()
+
+	components := []*Component{}
+	for rows.Next() {
+		var component Component
+		if err := scanComponent(rows, &component); err != nil {
+			return nil, fmt.Errorf("failed to scan component: %w", err)
+		}
+		components = append(components, &component)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	if err := s.loadMonitorIDs(ctx, components...); err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// Update saves the fields, override and monitors of component.
+func (s *ComponentStore) Update(ctx context.Context, component *Component) error {
+	query := `
+    UPDATE status_page_components
+    SET group_id = $1, name = $2, description = $3, position = $4, rule = $5,
+      override_status = $6, override_message = $7, override_expires_at = $8
+    WHERE id = $9
+    RETURNING updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	overrideStatus, overrideMessage, overrideExpiresAt := overrideColumns(component)
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		nullableID(component.GroupID),
+		component.Name,
+		component.Description,
+		component.Position,
+		component.Rule,
+		overrideStatus,
+		overrideMessage,
+		overrideExpiresAt,
+		component.ID,
+	).Scan(&component.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	if err := setComponentMonitors(ctx, tx, component.ID, component.MonitorIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *ComponentStore) Delete(ctx context.Context, id string) error {
+	query := `
+    DELETE FROM status_page_components
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
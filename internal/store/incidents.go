@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	IncidentOpen     = "open"
+	IncidentResolved = "resolved"
+)
+
+type Incident struct {
+	ID         string     `json:"id"`
+	MonitorID  string     `json:"monitor_id"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	// Duration in seconds, set once the incident is resolved.
+	Duration   *int64 `json:"duration"`
+	FirstError string `json:"first_error"`
+	LastError  string `json:"last_error"`
+}
+
+// IncidentFilter narrows down listed incidents. Zero values do not filter.
+// From and To select incidents that overlap the range.
+type IncidentFilter struct {
+	MonitorID string
+	Status    string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+type IncidentStore struct {
+	db *sql.DB
+}
+
+const incidentColumns = `id, monitor_id, status, started_at, resolved_at, duration, first_error, last_error`
+
+func scanIncident(row interface{ Scan(...any) error }, incident *Incident) error {
+	var resolvedAt sql.NullTime
+	var duration sql.NullInt64
+
+	err := row.Scan(
+		&incident.ID,
+		&incident.MonitorID,
+		&incident.Status,
+		&incident.StartedAt,
+		&resolvedAt,
+		&duration,
+		&incident.FirstError,
+		&incident.LastError,
+	)
+	if err != nil {
+		return err
+	}
+
+	incident.ResolvedAt = nil
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+
+	incident.Duration = nil
+	if duration.Valid {
+		incident.Duration = &duration.Int64
+	}
+
+	return nil
+}
+
+func (s *IncidentStore) Create(ctx context.Context, incident *Incident) error {
+	query := `
+    INSERT INTO incidents (id, monitor_id, status, started_at, first_error, last_error)
+    VALUES ($1, $2, $3, $4, $5, $6);
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(
+		ctx,
+		query,
+		incident.ID,
+		incident.MonitorID,
+		incident.Status,
+		formatTime(incident.StartedAt),
+		incident.FirstError,
+		incident.LastError,
+	)
+
+	return err
+}
+
+func (s *IncidentStore) GetOpenByMonitorID(ctx context.Context, monitorID string) (*Incident, error) {
+	query := `
+    SELECT ` + incidentColumns + `
+    FROM incidents
+    WHERE monitor_id = $1 AND status = 'open'
+    ORDER BY started_at DESC
+    LIMIT 1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var incident Incident
+
+	err := scanIncident(s.db.QueryRowContext(ctx, query, monitorID), &incident)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &incident, nil
+}
+
+// Update saves the status, resolution and last error of an incident.
+func (s *IncidentStore) Update(ctx context.Context, incident *Incident) error {
+	query := `
+    UPDATE incidents
+    SET
+      status = $1,
+      resolved_at = $2,
+      duration = $3,
+      last_error = $4
+    WHERE id = $5;
+  `
+
+	var resolvedAt any
+	if incident.ResolvedAt != nil {
+		resolvedAt = formatTime(*incident.ResolvedAt)
+	}
+
+	var duration any
+	if incident.Duration != nil {
+		duration = *incident.Duration
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(
+		ctx,
+		query,
+		incident.Status,
+		resolvedAt,
+		duration,
+		incident.LastError,
+		incident.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List returns incidents matching filter, most recent first.
+func (s *IncidentStore) List(ctx context.Context, filter IncidentFilter) ([]*Incident, error) {
+	var conditions []string
+	var args []any
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MonitorID != "" {
+		where("monitor_id = $%d", filter.MonitorID)
+	}
+	if filter.Status != "" {
+		where("status = $%d", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		where("(resolved_at IS NULL OR resolved_at >= $%d)", formatTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where("started_at < $%d", formatTime(filter.To))
+	}
+
+	query := `
+    SELECT ` + incidentColumns + `
+    FROM incidents`
+
+	if len(conditions) > 0 {
+		query += `
+    WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	query += `
+    ORDER BY started_at DESC`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(`
+    LIMIT $%d`, len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query+";", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := []*Incident{}
+	for rows.Next() {
+		var incident Incident
+		if err := scanIncident(rows, &incident); err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, &incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return incidents, nil
+}
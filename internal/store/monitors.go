@@ -3,39 +3,137 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type Monitor struct {
-	ID        string `json:"id"`
-	UserId    string `json:"user_id"`
-	Name      string `json:"name"`
-	Address   string `json:"address"`
-	Method    string `json:"method"`
-	Kind      string `json:"kind"`
-	Config    string `json:"config"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
-	Interval  int    `json:"interval"`
-	Version   int    `json:"version"`
+	ID            string          `json:"id"`
+	UserId        string          `json:"user_id"`
+	Name          string          `json:"name"`
+	Address       string          `json:"address"`
+	Method        string          `json:"method"`
+	Kind          string          `json:"kind"`
+	Config        json.RawMessage `json:"config" swaggertype:"object"`
+	CreatedAt     string          `json:"created_at"`
+	UpdatedAt     string          `json:"updated_at"`
+	Interval      int             `json:"interval"`
+	Version       int             `json:"version"`
+	Retries       int             `json:"retries"`
+	RetryInterval int             `json:"retry_interval"`
+	PushToken     string          `json:"push_token,omitempty"`
+	Retention     *Retention      `json:"retention,omitempty"`
+	// BadgeEnabled makes the status and uptime badges of the monitor public.
+	BadgeEnabled bool `json:"badge_enabled"`
+}
+
+// Retention overrides how many days the results of a monitor are kept. Nil
+// fields fall back to the global policy, and 0 keeps results forever.
+type Retention struct {
+	RawDays    *int `json:"raw_days,omitempty" validate:"omitempty,gte=0"`
+	MinuteDays *int `json:"minute_days,omitempty" validate:"omitempty,gte=0"`
+	HourDays   *int `json:"hour_days,omitempty" validate:"omitempty,gte=0"`
+	DayDays    *int `json:"day_days,omitempty" validate:"omitempty,gte=0"`
 }
 
 type MonitorStore struct {
 	db *sql.DB
 }
 
+const monitorColumns = `id, user_id, name, address, method, kind, config, created_at, updated_at, interval, version, push_token, retries, retry_interval, retention, badge_enabled`
+
+func scanMonitor(row interface{ Scan(...any) error }, monitor *Monitor) error {
+	var config, pushToken, retention sql.NullString
+
+	err := row.Scan(
+		&monitor.ID,
+		&monitor.UserId,
+		&monitor.Name,
+		&monitor.Address,
+		&monitor.Method,
+		&monitor.Kind,
+		&config,
+		&monitor.CreatedAt,
+		&monitor.UpdatedAt,
+		&monitor.Interval,
+		&monitor.Version,
+		&pushToken,
+		&monitor.Retries,
+		&monitor.RetryInterval,
+		&retention,
+		&monitor.BadgeEnabled,
+	)
+	if err != nil {
+		return err
+	}
+
+	monitor.PushToken = pushToken.String
+
+	monitor.Config = nil
+	if config.Valid && config.String != "" {
+		monitor.Config = json.RawMessage(config.String)
+	}
+
+	monitor.Retention = nil
+	if retention.Valid && retention.String != "" {
+		if err := json.Unmarshal([]byte(retention.String), &monitor.Retention); err != nil {
+			return fmt.Errorf("failed to decode retention: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+// nullableJSON stores an empty document as NULL rather than an empty string,
+// which is not valid JSON.
+func nullableJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return string(raw)
+}
+
+// nullableRetention stores a missing retention override as NULL.
+func nullableRetention(retention *Retention) (any, error) {
+	if retention == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(retention)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(raw), nil
+}
+
 func (s *MonitorStore) Create(ctx context.Context, monitor *Monitor) error {
 	query := `
-    INSERT INTO monitors (id, user_id, name, address, interval, method, kind, config)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    INSERT INTO monitors (id, user_id, name, address, interval, method, kind, config, push_token, retries, retry_interval, retention, badge_enabled)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
     RETURNING id, created_at, updated_at;
   `
 
+	retention, err := nullableRetention(monitor.Retention)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	err := s.db.QueryRowContext(
+	err = s.db.QueryRowContext(
 		ctx,
 		query,
 		monitor.ID,
@@ -45,7 +143,12 @@ func (s *MonitorStore) Create(ctx context.Context, monitor *Monitor) error {
 		monitor.Interval,
 		monitor.Method,
 		monitor.Kind,
-		monitor.Config,
+		nullableJSON(monitor.Config),
+		nullableString(monitor.PushToken),
+		monitor.Retries,
+		monitor.RetryInterval,
+		retention,
+		monitor.BadgeEnabled,
 	).Scan(&monitor.ID, &monitor.CreatedAt, &monitor.UpdatedAt)
 	if err != nil {
 		return err
@@ -56,7 +159,7 @@ func (s *MonitorStore) Create(ctx context.Context, monitor *Monitor) error {
 
 func (s *MonitorStore) GetByID(ctx context.Context, id string) (*Monitor, error) {
 	query := `
-    SELECT id, user_id, name, address, method, kind, config, created_at, updated_at, interval, version
+    SELECT ` + monitorColumns + `
     FROM monitors
     WHERE id = $1;
   `
@@ -66,19 +169,32 @@ func (s *MonitorStore) GetByID(ctx context.Context, id string) (*Monitor, error)
 
 	var monitor Monitor
 
-	err := s.db.QueryRowContext(ctx, query, id).Scan(
-		&monitor.ID,
-		&monitor.UserId,
-		&monitor.Name,
-		&monitor.Address,
-		&monitor.Method,
-		&monitor.Kind,
-		&monitor.Config,
-		&monitor.CreatedAt,
-		&monitor.UpdatedAt,
-		&monitor.Interval,
-		&monitor.Version,
-	)
+	err := scanMonitor(s.db.QueryRowContext(ctx, query, id), &monitor)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &monitor, nil
+}
+
+func (s *MonitorStore) GetByPushToken(ctx context.Context, token string) (*Monitor, error) {
+	query := `
+    SELECT ` + monitorColumns + `
+    FROM monitors
+    WHERE push_token = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var monitor Monitor
+
+	err := scanMonitor(s.db.QueryRowContext(ctx, query, token), &monitor)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -93,7 +209,7 @@ func (s *MonitorStore) GetByID(ctx context.Context, id string) (*Monitor, error)
 
 func (s *MonitorStore) List(ctx context.Context) ([]*Monitor, error) {
 	query := `
-    SELECT id, user_id, name, address, method, kind, config, created_at, updated_at, interval, version
+    SELECT ` + monitorColumns + `
     FROM monitors;
   `
 
@@ -109,20 +225,52 @@ func (s *MonitorStore) List(ctx context.Context) ([]*Monitor, error) {
 	var monitors []*Monitor
 	for rows.Next() {
 		var monitor Monitor
-		err := rows.Scan(
-			&monitor.ID,
-			&monitor.UserId,
-			&monitor.Name,
-			&monitor.Address,
-			&monitor.Method,
-			&monitor.Kind,
-			&monitor.Config,
-			&monitor.CreatedAt,
-			&monitor.UpdatedAt,
-			&monitor.Interval,
-			&monitor.Version,
-		)
-		if err != nil {
+		if err := scanMonitor(rows, &monitor); err != nil {
+			return nil, fmt.Errorf("failed to scan monitor: %w", err)
+		}
+		monitors = append(monitors, &monitor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return monitors, nil
+}
+
+// ListByIDs returns the monitors with the given IDs that exist, in no
+// particular order.
+func (s *MonitorStore) ListByIDs(ctx context.Context, ids []string) ([]*Monitor, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := `
+    SELECT ` + monitorColumns + `
+    FROM monitors
+    WHERE id IN (` + strings.Join(placeholders, ", ") + `);
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch monitors: %w", err)
+	}
+	defer rows.Close()
+
+	var monitors []*Monitor
+	for rows.Next() {
+		var monitor Monitor
+		if err := scanMonitor(rows, &monitor); err != nil {
 			return nil, fmt.Errorf("failed to scan monitor: %w", err)
 		}
 		monitors = append(monitors, &monitor)
@@ -171,15 +319,25 @@ func (s *MonitorStore) Update(ctx context.Context, monitor *Monitor) error {
       method = COALESCE($4, method),
       kind = COALESCE($5, kind),
       config = COALESCE($6, config),
+      push_token = COALESCE($7, push_token),
+      retries = COALESCE($8, retries),
+      retry_interval = COALESCE($9, retry_interval),
+      retention = $10,
+      badge_enabled = $11,
       version = version + 1
-    WHERE id = $7 AND version = $8
+    WHERE id = $12 AND version = $13
     RETURNING version;
   `
 
+	retention, err := nullableRetention(monitor.Retention)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	err := s.db.QueryRowContext(
+	err = s.db.QueryRowContext(
 		ctx,
 		query,
 		monitor.Name,
@@ -187,7 +345,12 @@ func (s *MonitorStore) Update(ctx context.Context, monitor *Monitor) error {
 		monitor.Interval,
 		monitor.Method,
 		monitor.Kind,
-		monitor.Config,
+		nullableJSON(monitor.Config),
+		nullableString(monitor.PushToken),
+		monitor.Retries,
+		monitor.RetryInterval,
+		retention,
+		monitor.BadgeEnabled,
 		monitor.ID,
 		monitor.Version,
 	).Scan(&monitor.Version)
@@ -3,14 +3,62 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type PingResult struct {
-	ID           string `json:"id"`
-	MonitorID    string `json:"monitor_id"`
-	Status       string `jhson:"status"`
-	Timestamp    string `json:"timestamp"`
-	ResponseTime int    `json:"response_time"`
+	ID           string    `json:"id"`
+	MonitorID    string    `json:"monitor_id"`
+	Status       string    `json:"status"`
+	Timestamp    time.Time `json:"timestamp"`
+	ResponseTime int       `json:"response_time"`
+	Message      string    `json:"message,omitempty"`
+}
+
+// PingResultFilter selects ping results. Zero values do not filter.
+type PingResultFilter struct {
+	MonitorID string
+	From      time.Time
+	To        time.Time
+	Statuses  []string
+	Limit     int
+	// After continues a listing after the result the cursor points at.
+	After *PingResultCursor
+}
+
+// PingResultCursor points at a result in a listing ordered newest first.
+type PingResultCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (c PingResultCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(formatTime(c.Timestamp) + "|" + c.ID))
+}
+
+func DecodePingResultCursor(encoded string) (*PingResultCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(timeFormat, timestamp)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &PingResultCursor{Timestamp: t, ID: id}, nil
 }
 
 type PingResultStore struct {
@@ -19,8 +67,8 @@ type PingResultStore struct {
 
 func (s *PingResultStore) Create(ctx context.Context, pingResult *PingResult) error {
 	query := `
-    INSERT INTO ping_results (monitor_id, status, response_time, timestamp)
-    VALUES ($1, $2, $3, $4)
+    INSERT INTO ping_results (id, monitor_id, status, response_time, timestamp, message)
+    VALUES ($1, $2, $3, $4, $5, $6)
     RETURNING id, timestamp
   `
 
@@ -30,10 +78,12 @@ func (s *PingResultStore) Create(ctx context.Context, pingResult *PingResult) er
 	err := s.db.QueryRowContext(
 		ctx,
 		query,
+		pingResult.ID,
 		pingResult.MonitorID,
 		pingResult.Status,
 		pingResult.ResponseTime,
-		pingResult.Timestamp,
+		formatTime(pingResult.Timestamp),
+		pingResult.Message,
 	).Scan(&pingResult.ID, &pingResult.Timestamp)
 	if err != nil {
 		return err
@@ -41,3 +91,324 @@ func (s *PingResultStore) Create(ctx context.Context, pingResult *PingResult) er
 
 	return nil
 }
+
+// pingResultsPerInsert caps the rows of one multi-row insert, keeping its
+// parameters well below SQLite's limit.
+const pingResultsPerInsert = 100
+
+// CreateBatch stores results in a single transaction. Results of monitors
+// that have been deleted meanwhile are left out rather than failing the
+// whole batch on the foreign key.
+func (s *PingResultStore) CreateBatch(ctx context.Context, pingResults []*PingResult) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(pingResults); start += pingResultsPerInsert {
+		end := min(start+pingResultsPerInsert, len(pingResults))
+
+		values := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*6)
+		for _, pingResult := range pingResults[start:end] {
+			n := len(args)
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+			args = append(
+				args,
+				pingResult.ID,
+				pingResult.MonitorID,
+				pingResult.Status,
+				pingResult.ResponseTime,
+				formatTime(pingResult.Timestamp),
+				pingResult.Message,
+			)
+		}
+
+		query := `
+    INSERT INTO ping_results (id, monitor_id, status, response_time, timestamp, message)
+    SELECT column1, column2, column3, column4, column5, column6
+    FROM (VALUES ` + strings.Join(values, ", ") + `)
+    WHERE column2 IN (SELECT id FROM monitors);`
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLatestConfirmed returns the most recent result of a monitor that is not
+// a pending retry.
+func (s *PingResultStore) GetLatestConfirmed(ctx context.Context, monitorID string) (*PingResult, error) {
+	query := `
+    SELECT id, monitor_id, status, timestamp, response_time, message
+    FROM ping_results
+    WHERE monitor_id = $1 AND status != 'pending'
+    ORDER BY timestamp DESC
+    LIMIT 1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var pingResult PingResult
+
+	err := s.db.QueryRowContext(ctx, query, monitorID).Scan(
+		&pingResult.ID,
+		&pingResult.MonitorID,
+		&pingResult.Status,
+		&pingResult.Timestamp,
+		&pingResult.ResponseTime,
+		&pingResult.Message,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &pingResult, nil
+}
+
+// GetOldestTimestamp returns the timestamp of the oldest stored result.
+func (s *PingResultStore) GetOldestTimestamp(ctx context.Context) (time.Time, error) {
+	query := `
+    SELECT timestamp
+    FROM ping_results
+    ORDER BY timestamp
+    LIMIT 1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var timestamp time.Time
+
+	err := s.db.QueryRowContext(ctx, query).Scan(&timestamp)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return timestamp, ErrNotFound
+		default:
+			return timestamp, err
+		}
+	}
+
+	return timestamp.UTC(), nil
+}
+
+// List returns the results matching filter, newest first.
+func (s *PingResultStore) List(ctx context.Context, filter PingResultFilter) ([]*PingResult, error) {
+	var args []any
+	var conditions []string
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MonitorID != "" {
+		where("monitor_id = $%d", filter.MonitorID)
+	}
+
+	if !filter.From.IsZero() {
+		where("timestamp >= $%d", formatTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where("timestamp < $%d", formatTime(filter.To))
+	}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			args = append(args, status)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.After != nil {
+		args = append(args, formatTime(filter.After.Timestamp), filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(timestamp < $%[1]d OR (timestamp = $%[1]d AND id < $%[2]d))", len(args)-1, len(args),
+		))
+	}
+
+	query := `
+    SELECT id, monitor_id, status, timestamp, response_time, message
+    FROM ping_results`
+
+	if len(conditions) > 0 {
+		query += `
+    WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	query += `
+    ORDER BY timestamp DESC, id DESC`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(`
+    LIMIT $%d`, len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query+";", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ping results: %w", err)
+	}
+	defer rows.Close()
+
+	pingResults := []*PingResult{}
+	for rows.Next() {
+		var pingResult PingResult
+		err := rows.Scan(
+			&pingResult.ID,
+			&pingResult.MonitorID,
+			&pingResult.Status,
+			&pingResult.Timestamp,
+			&pingResult.ResponseTime,
+			&pingResult.Message,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ping result: %w", err)
+		}
+		pingResults = append(pingResults, &pingResult)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return pingResults, nil
+}
+
+// PingResultSummary aggregates the confirmed results of a monitor. Response
+// times are in milliseconds and only cover results that were up or degraded.
+type PingResultSummary struct {
+	Checks          int
+	Up              int
+	Degraded        int
+	Down            int
+	ResponseTimeSum int64
+	// ResponseTimes counts the results by response time.
+	ResponseTimes map[int]int
+}
+
+// Summarize aggregates the confirmed results of a monitor with timestamps in
+// [from, to) in the database, rather than reading every result.
+func (s *PingResultStore) Summarize(ctx context.Context, monitorID string, from, to time.Time) (*PingResultSummary, error) {
+	query := `
+    SELECT
+      COUNT(*),
+      COALESCE(SUM(status = 'up'), 0),
+      COALESCE(SUM(status = 'degraded'), 0),
+      COALESCE(SUM(status = 'down'), 0),
+      COALESCE(SUM(CASE WHEN status != 'down' THEN response_time END), 0)
+    FROM ping_results
+    WHERE monitor_id = $1 AND timestamp >= $2 AND timestamp < $3 AND status != 'pending';
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	summary := &PingResultSummary{ResponseTimes: make(map[int]int)}
+
+	err := s.db.QueryRowContext(ctx, query, monitorID, formatTime(from), formatTime(to)).Scan(
+		&summary.Checks,
+		&summary.Up,
+		&summary.Degraded,
+		&summary.Down,
+		&summary.ResponseTimeSum,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.Up+summary.Degraded == 0 {
+		return summary, nil
+	}
+
+	query = `
+    SELECT response_time, COUNT(*)
+    FROM ping_results
+    WHERE monitor_id = $1 AND timestamp >= $2 AND timestamp < $3 AND status IN ('up', 'degraded')
+    GROUP BY response_time;
+  `
+
+	rows, err := s.db.QueryContext(ctx, query, monitorID, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var responseTime, count int
+		if err := rows.Scan(&responseTime, &count); err != nil {
+			return nil, err
+		}
+		summary.ResponseTimes[responseTime] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// DeleteBefore deletes up to limit results of a monitor older than before and
+// returns how many it deleted.
+func (s *PingResultStore) DeleteBefore(ctx context.Context, monitorID string, before time.Time, limit int) (int64, error) {
+	query := `
+    DELETE FROM ping_results
+    WHERE rowid IN (
+      SELECT rowid
+      FROM ping_results
+      WHERE monitor_id = $1 AND timestamp < $2
+      LIMIT $3
+    );
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, monitorID, formatTime(before), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteOrphans deletes up to limit results whose monitor no longer exists,
+// such as those left over from before foreign keys were enforced.
+func (s *PingResultStore) DeleteOrphans(ctx context.Context, limit int) (int64, error) {
+	query := `
+    DELETE FROM ping_results
+    WHERE rowid IN (
+      SELECT rowid
+      FROM ping_results
+      WHERE monitor_id NOT IN (SELECT id FROM monitors)
+      LIMIT $1
+    );
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
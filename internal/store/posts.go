@@ -0,0 +1,517 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PostIncident    = "incident"
+	PostMaintenance = "maintenance"
+)
+
+// Statuses of incident posts.
+const (
+	PostInvestigating = "investigating"
+	PostIdentified    = "identified"
+	PostMonitoring    = "monitoring"
+	PostResolved      = "resolved"
+)
+
+// Statuses of maintenance posts.
+const (
+	PostScheduled  = "scheduled"
+	PostInProgress = "in_progress"
+	PostCompleted  = "completed"
+)
+
+// Post is a human-written announcement on a status page: an incident with a
+// timeline of updates, or a scheduled maintenance.
+type Post struct {
+	ID           string   `json:"id"`
+	StatusPageID string   `json:"status_page_id"`
+	Kind         string   `json:"kind"`
+	Title        string   `json:"title"`
+	Severity     string   `json:"severity,omitempty"`
+	Status       string   `json:"status"`
+	MonitorIDs   []string `json:"monitors"`
+	// StartsAt is when an incident was posted or a maintenance begins.
+	StartsAt time.Time `json:"starts_at"`
+	// EndsAt is when an incident was resolved or a maintenance ends.
+	EndsAt    *time.Time    `json:"ends_at"`
+	CreatedAt string        `json:"created_at"`
+	UpdatedAt string        `json:"updated_at"`
+	Updates   []*PostUpdate `json:"updates"`
+}
+
+// PostUpdate is one entry in the timeline of a post.
+type PostUpdate struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"post_id"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostFilter narrows down listed posts. Zero values do not filter. From
+// selects posts that had not ended by then.
+type PostFilter struct {
+	StatusPageID string
+	Kind         string
+	From         time.Time
+	Limit        int
+}
+
+// MaintenanceWindow is a time range during which a monitor is under
+// scheduled maintenance.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+type PostStore struct {
+	db *sql.DB
+}
+
+const postColumns = `id, status_page_id, kind, title, severity, status, starts_at, ends_at, created_at, updated_at`
+
+func scanPost(row interface{ Scan(...any) error }, post *Post) error {
+	var endsAt sql.NullTime
+
+	err := row.Scan(
+		&post.ID,
+		&post.StatusPageID,
+		&post.Kind,
+		&post.Title,
+		&post.Severity,
+		&post.Status,
+		&post.StartsAt,
+		&endsAt,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	post.EndsAt = nil
+	if endsAt.Valid {
+		post.EndsAt = &endsAt.Time
+	}
+
+	return nil
+}
+
+func nullableTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return formatTime(*t)
+}
+
+// Create stores post together with its monitors and initial updates.
+func (s *PostStore) Create(ctx context.Context, post *Post) error {
+	query := `
+    INSERT INTO status_page_posts (id, status_page_id, kind, title, severity, status, starts_at, ends_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    RETURNING created_at, updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		post.ID,
+		post.StatusPageID,
+		post.Kind,
+		post.Title,
+		post.Severity,
+		post.Status,
+		formatTime(post.StartsAt),
+		nullableTime(post.EndsAt),
+	).Scan(&post.CreatedAt, &post.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := setPostMonitors(ctx, tx, post.ID, post.MonitorIDs); err != nil {
+		return err
+	}
+
+	for _, update := range post.Updates {
+		update.PostID = post.ID
+		if err := insertPostUpdate(ctx, tx, update); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// setPostMonitors replaces the monitors affected by a post.
+func setPostMonitors(ctx context.Context, tx *sql.Tx, postID string, monitorIDs []string) error {
+	query := `
+    DELETE FROM status_page_post_monitors
+    WHERE post_id = $1;
+  `
+
+	if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+		return err
+	}
+
+	if len(monitorIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(monitorIDs))
+	args := []any{postID}
+	for i, monitorID := range monitorIDs {
+		args = append(args, monitorID)
+		values[i] = fmt.Sprintf("($1, $%d)", len(args))
+	}
+
+	query = `
+    INSERT INTO status_page_post_monitors (post_id, monitor_id)
+    VALUES ` + strings.Join(values, ", ") + `;`
+
+	_, err := tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func insertPostUpdate(ctx context.Context, tx *sql.Tx, update *PostUpdate) error {
+	query := `
+    INSERT INTO status_page_post_updates (id, post_id, status, message, created_at)
+    VALUES ($1, $2, $3, $4, $5);
+  `
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		update.ID,
+		update.PostID,
+		update.Status,
+		update.Message,
+		formatTime(update.CreatedAt),
+	)
+
+	return err
+}
+
+// loadDetails fills in the monitors and updates of posts.
+func (s *PostStore) loadDetails(ctx context.Context, posts ...*Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Post, len(posts))
+	placeholders := make([]string, len(posts))
+	args := make([]any, len(posts))
+	for i, post := range posts {
+		post.MonitorIDs = []string{}
+		post.Updates = []*PostUpdate{}
+		byID[post.ID] = post
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = post.ID
+	}
+	in := strings.Join(placeholders, ", ")
+
+	query := `
+    SELECT post_id, monitor_id
+    FROM status_page_post_monitors
+    WHERE post_id IN (` + in + `)
+    ORDER BY rowid;`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch post monitors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, monitorID string
+		if err := rows.Scan(&postID, &monitorID); err != nil {
+			return fmt.Errorf("failed to scan post monitor: %w", err)
+		}
+		byID[postID].MonitorIDs = append(byID[postID].MonitorIDs, monitorID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query = `
+    SELECT id, post_id, status, message, created_at
+    FROM status_page_post_updates
+    WHERE post_id IN (` + in + `)
+    ORDER BY created_at DESC, rowid DESC;`
+
+	rows, err = s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch post updates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var update PostUpdate
+		if err := rows.Scan(&update.ID, &update.PostID, &update.Status, &update.Message, &update.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan post update: %w", err)
+		}
+		byID[update.PostID].Updates = append(byID[update.PostID].Updates, &update)
+	}
+
+	return rows.Err()
+}
+
+func (s *PostStore) GetByID(ctx context.Context, id string) (*Post, error) {
+	query := `
+    SELECT ` + postColumns + `
+    FROM status_page_posts
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var post Post
+
+	err := scanPost(s.db.QueryRowContext(ctx, query, id), &post)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := s.loadDetails(ctx, &post); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+// List returns the posts matching filter, latest starting first. Updates
+// are listed newest first.
+func (s *PostStore) List(ctx context.Context, filter PostFilter) ([]*Post, error) {
+	var args []any
+	var conditions []string
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.StatusPageID != "" {
+		where("status_page_id = $%d", filter.StatusPageID)
+	}
+	if filter.Kind != "" {
+		where("kind = $%d", filter.Kind)
+	}
+	if !filter.From.IsZero() {
+		where("(ends_at IS NULL OR ends_at >= $%d)", formatTime(filter.From))
+	}
+
+	query := `
+    SELECT ` + postColumns + `
+    FROM status_page_posts`
+
+	if len(conditions) > 0 {
+		query += `
+    WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	query += `
+    ORDER BY starts_at DESC, id`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(`
+    LIMIT $%d`, len(args))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query+";", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := []*Post{}
+	for rows.Next() {
+		var post Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	if err := s.loadDetails(ctx, posts...); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Update saves the fields and monitors of post. Its updates are left alone.
+func (s *PostStore) Update(ctx context.Context, post *Post) error {
+	query := `
+    UPDATE status_page_posts
+    SET title = $1, severity = $2, status = $3, starts_at = $4, ends_at = $5
+    WHERE id = $6
+    RETURNING updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		post.Title,
+		post.Severity,
+		post.Status,
+		formatTime(post.StartsAt),
+		nullableTime(post.EndsAt),
+		post.ID,
+	).Scan(&post.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	if err := setPostMonitors(ctx, tx, post.ID, post.MonitorIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddUpdate appends update to the timeline of post and saves the status and
+// end time of post, which the update may have changed.
+func (s *PostStore) AddUpdate(ctx context.Context, post *Post, update *PostUpdate) error {
+	query := `
+    UPDATE status_page_posts
+    SET status = $1, ends_at = $2
+    WHERE id = $3
+    RETURNING updated_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, query, post.Status, nullableTime(post.EndsAt), post.ID).Scan(&post.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	update.PostID = post.ID
+	if err := insertPostUpdate(ctx, tx, update); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostStore) Delete(ctx context.Context, id string) error {
+	query := `
+    DELETE FROM status_page_posts
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListMaintenanceWindows returns the maintenance of a monitor that overlaps
+// [from, to), ordered by start.
+func (s *PostStore) ListMaintenanceWindows(ctx context.Context, monitorID string, from, to time.Time) ([]MaintenanceWindow, error) {
+	query := `
+    SELECT p.starts_at, p.ends_at
+    FROM status_page_posts p
+    JOIN status_page_post_monitors m ON m.post_id = p.id
+    WHERE m.monitor_id = $1 AND p.kind = 'maintenance' AND p.ends_at > $2 AND p.starts_at < $3
+    ORDER BY p.starts_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, monitorID, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []MaintenanceWindow
+	for rows.Next() {
+		var window MaintenanceWindow
+		if err := rows.Scan(&window.Start, &window.End); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return windows, nil
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	ResolutionMinute = "minute"
+	ResolutionHour   = "hour"
+	ResolutionDay    = "day"
+)
+
+// Rollup aggregates the confirmed results of a monitor within one bucket.
+// Response times are in milliseconds and only cover results that were up or
+// degraded. ResponseTimes is a histogram of them, keyed by bucket index.
+type Rollup struct {
+	MonitorID       string      `json:"monitor_id"`
+	Resolution      string      `json:"resolution"`
+	Bucket          time.Time   `json:"bucket"`
+	Checks          int         `json:"checks"`
+	Up              int         `json:"up"`
+	Degraded        int         `json:"degraded"`
+	Down            int         `json:"down"`
+	ResponseTimeSum int64       `json:"response_time_sum"`
+	P50ResponseTime int         `json:"response_time_p50"`
+	P95ResponseTime int         `json:"response_time_p95"`
+	P99ResponseTime int         `json:"response_time_p99"`
+	ResponseTimes   map[int]int `json:"-"`
+}
+
+// RollupFilter selects the rollups of one resolution with buckets in
+// [From, To). An empty MonitorID selects every monitor.
+type RollupFilter struct {
+	Resolution string
+	MonitorID  string
+	From       time.Time
+	To         time.Time
+}
+
+type RollupStore struct {
+	db *sql.DB
+}
+
+// List returns the rollups matching filter, oldest first.
+func (s *RollupStore) List(ctx context.Context, filter RollupFilter) ([]*Rollup, error) {
+	args := []any{filter.Resolution, formatTime(filter.From), formatTime(filter.To)}
+	conditions := []string{"resolution = $1", "bucket >= $2", "bucket < $3"}
+
+	if filter.MonitorID != "" {
+		args = append(args, filter.MonitorID)
+		conditions = append(conditions, fmt.Sprintf("monitor_id = $%d", len(args)))
+	}
+
+	query := `
+    SELECT monitor_id, resolution, bucket, checks, up, degraded, down, response_time_sum,
+      response_time_p50, response_time_p95, response_time_p99, response_times
+    FROM ping_result_rollups
+    WHERE ` + strings.Join(conditions, " AND ") + `
+    ORDER BY bucket;`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rollups: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := []*Rollup{}
+	for rows.Next() {
+		var rollup Rollup
+		var responseTimes string
+
+		err := rows.Scan(
+			&rollup.MonitorID,
+			&rollup.Resolution,
+			&rollup.Bucket,
+			&rollup.Checks,
+			&rollup.Up,
+			&rollup.Degraded,
+			&rollup.Down,
+			&rollup.ResponseTimeSum,
+			&rollup.P50ResponseTime,
+			&rollup.P95ResponseTime,
+			&rollup.P99ResponseTime,
+			&responseTimes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rollup: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(responseTimes), &rollup.ResponseTimes); err != nil {
+			return nil, fmt.Errorf("failed to decode rollup response times: %w", err)
+		}
+
+		rollups = append(rollups, &rollup)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return rollups, nil
+}
+
+// Save stores rollups, replacing existing buckets, and moves the watermark
+// of resolution to until in a single transaction.
+func (s *RollupStore) Save(ctx context.Context, resolution string, rollups []*Rollup, until time.Time) error {
+	insert := `
+    INSERT INTO ping_result_rollups (
+      monitor_id, resolution, bucket, checks, up, degraded, down, response_time_sum,
+      response_time_p50, response_time_p95, response_time_p99, response_times
+    )
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    ON CONFLICT (resolution, monitor_id, bucket) DO UPDATE SET
+      checks = excluded.checks,
+      up = excluded.up,
+      degraded = excluded.degraded,
+      down = excluded.down,
+      response_time_sum = excluded.response_time_sum,
+      response_time_p50 = excluded.response_time_p50,
+      response_time_p95 = excluded.response_time_p95,
+      response_time_p99 = excluded.response_time_p99,
+      response_times = excluded.response_times;
+  `
+
+	watermark := `
+    INSERT INTO rollup_watermarks (resolution, rolled_until)
+    VALUES ($1, $2)
+    ON CONFLICT (resolution) DO UPDATE SET rolled_until = excluded.rolled_until;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rollup := range rollups {
+		responseTimes, err := json.Marshal(rollup.ResponseTimes)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			insert,
+			rollup.MonitorID,
+			resolution,
+			formatTime(rollup.Bucket),
+			rollup.Checks,
+			rollup.Up,
+			rollup.Degraded,
+			rollup.Down,
+			rollup.ResponseTimeSum,
+			rollup.P50ResponseTime,
+			rollup.P95ResponseTime,
+			rollup.P99ResponseTime,
+			string(responseTimes),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save rollup: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, watermark, resolution, formatTime(until)); err != nil {
+		return fmt.Errorf("failed to save rollup watermark: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Watermarks returns, per resolution, the time up to which results have
+// been rolled up. Resolutions that were never rolled up are missing.
+func (s *RollupStore) Watermarks(ctx context.Context) (map[string]time.Time, error) {
+	query := `
+    SELECT resolution, rolled_until
+    FROM rollup_watermarks;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rollup watermarks: %w", err)
+	}
+	defer rows.Close()
+
+	watermarks := make(map[string]time.Time)
+	for rows.Next() {
+		var resolution string
+		var rolledUntil time.Time
+
+		if err := rows.Scan(&resolution, &rolledUntil); err != nil {
+			return nil, fmt.Errorf("failed to scan rollup watermark: %w", err)
+		}
+		watermarks[resolution] = rolledUntil.UTC()
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return watermarks, nil
+}
+
+// DeleteBefore deletes up to limit rollups of a monitor at resolution with
+// buckets older than before and returns how many it deleted.
+func (s *RollupStore) DeleteBefore(ctx context.Context, resolution, monitorID string, before time.Time, limit int) (int64, error) {
+	query := `
+    DELETE FROM ping_result_rollups
+    WHERE rowid IN (
+      SELECT rowid
+      FROM ping_result_rollups
+      WHERE resolution = $1 AND monitor_id = $2 AND bucket < $3
+      LIMIT $4
+    );
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, resolution, monitorID, formatTime(before), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteOrphans deletes up to limit rollups at resolution whose monitor no
+// longer exists.
+func (s *RollupStore) DeleteOrphans(ctx context.Context, resolution string, limit int) (int64, error) {
+	query := `
+    DELETE FROM ping_result_rollups
+    WHERE rowid IN (
+      SELECT rowid
+      FROM ping_result_rollups
+      WHERE resolution = $1 AND monitor_id NOT IN (SELECT id FROM monitors)
+      LIMIT $2
+    );
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, resolution, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -3,6 +3,16 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDuplicateSlug     = errors.New("a status page with that slug already exists")
+	ErrDuplicateHostname = errors.New("another status page has already verified that hostname")
 )
 
 type StatusPage struct {
@@ -13,32 +23,436 @@ type StatusPage struct {
 	CreatedAt  string   `json:"created_at"`
 	UpdatedAt  string   `json:"updated_at"`
 	MonitorIDs []string `json:"monitors"`
+	// Password, when set, must be entered by visitors before they see the
+	// page.
+	Password password `json:"-"`
+	// PasswordProtected reports whether Password is set.
+	PasswordProtected bool `json:"password_protected"`
+	// AllowedIPs are CIDR blocks whose visitors see the page without a
+	// password. A page with allowed IPs but no password is only shown to
+	// them.
+	AllowedIPs []string `json:"allowed_ips"`
+	// Hostname is a custom domain the page is served on once verified.
+	Hostname           string     `json:"hostname"`
+	HostnameVerifiedAt *time.Time `json:"hostname_verified_at"`
+	// HostnameToken is the value the TXT record verifying Hostname holds.
+	HostnameToken string `json:"-"`
+}
+
+// HostnameVerified reports whether the page is served on its hostname.
+func (s *StatusPage) HostnameVerified() bool {
+	return s.Hostname != "" && s.HostnameVerifiedAt != nil
+}
+
+// Protected reports whether the page is hidden from some visitors.
+func (s *StatusPage) Protected() bool {
+	return s.Password.IsSet() || len(s.AllowedIPs) > 0
 }
 
 type StatusPagesStore struct {
 	db *sql.DB
 }
 
+const statusPageColumns = `id, user_id, name, slug, created_at, updated_at, password_hash, allowed_ips, hostname, hostname_verified_at, hostname_token`
+
+func scanStatusPage(row interface{ Scan(...any) error }, statusPage *StatusPage) error {
+	var allowedIPs, hostname, hostnameToken sql.NullString
+
+	err := row.Scan(
+		&statusPage.ID,
+		&statusPage.UserID,
+		&statusPage.Name,
+		&statusPage.Slug,
+		&statusPage.CreatedAt,
+		&statusPage.UpdatedAt,
+		&statusPage.Password.hash,
+		&allowedIPs,
+		&hostname,
+		&statusPage.HostnameVerifiedAt,
+		&hostnameToken,
+	)
+	if err != nil {
+		return err
+	}
+
+	statusPage.Hostname = hostname.String
+	statusPage.HostnameToken = hostnameToken.String
+
+	statusPage.PasswordProtected = statusPage.Password.IsSet()
+
+	statusPage.AllowedIPs = []string{}
+	if allowedIPs.Valid && allowedIPs.String != "" {
+		if err := json.Unmarshal([]byte(allowedIPs.String), &statusPage.AllowedIPs); err != nil {
+			return fmt.Errorf("failed to decode allowed IPs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// nullableAllowedIPs stores an empty allowlist as NULL.
+func nullableAllowedIPs(allowedIPs []string) (any, error) {
+	if len(allowedIPs) == 0 {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(allowedIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(raw), nil
+}
+
+// checkUnique returns ErrDuplicateSlug or ErrDuplicateHostname when another
+// status page has the slug of statusPage, or has verified its hostname while
+// statusPage has too. It runs in the transaction that saves statusPage, so
+// that the constraints it checks for are not hit.
+func checkUnique(ctx context.Context, tx *sql.Tx, statusPage *StatusPage) error {
+	query := `
+    SELECT
+      COALESCE(SUM(slug = $1), 0),
+      COALESCE(SUM(hostname = $2 AND hostname_verified_at IS NOT NULL), 0)
+    FROM status_pages
+    WHERE id != $3 AND (slug = $1 OR hostname = $2);
+  `
+
+	var hostname any
+	if statusPage.HostnameVerified() {
+		hostname = statusPage.Hostname
+	}
+
+	var slugs, hostnames int
+	if err := tx.QueryRowContext(ctx, query, statusPage.Slug, hostname, statusPage.ID).Scan(&slugs, &hostnames); err != nil {
+		return err
+	}
+
+	switch {
+	case slugs > 0:
+		return ErrDuplicateSlug
+	case hostnames > 0:
+		return ErrDuplicateHostname
+	default:
+		return nil
+	}
+}
+
 func (s *StatusPagesStore) Create(ctx context.Context, statusPage *StatusPage) error {
 	query := `
-    INSERT INTO status_pages (user_id, name, slug, monitor_ids)
-    VALUES ($1, $2, $3, $4)
+    INSERT INTO status_pages (id, user_id, name, slug, password_hash, allowed_ips, hostname, hostname_token)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     RETURNING id, created_at, updated_at
   `
 
+	allowedIPs, err := nullableAllowedIPs(statusPage.AllowedIPs)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	err := s.db.QueryRowContext(
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkUnique(ctx, tx, statusPage); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(
 		ctx,
 		query,
+		statusPage.ID,
 		statusPage.UserID,
 		statusPage.Name,
 		statusPage.Slug,
-		statusPage.MonitorIDs).Scan(&statusPage.ID, &statusPage.CreatedAt, &statusPage.UpdatedAt)
+		statusPage.Password.hash,
+		allowedIPs,
+		nullableString(statusPage.Hostname),
+		nullableString(statusPage.HostnameToken)).Scan(&statusPage.ID, &statusPage.CreatedAt, &statusPage.UpdatedAt)
 	if err != nil {
 		return err
 	}
 
+	if err := setStatusPageMonitors(ctx, tx, statusPage.ID, statusPage.MonitorIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setStatusPageMonitors replaces the monitors attached to a status page,
+// keeping them in the given order.
+func setStatusPageMonitors(ctx context.Context, tx *sql.Tx, statusPageID string, monitorIDs []string) error {
+	query := `
+    DELETE FROM status_page_monitors
+    WHERE status_page_id = $1;
+  `
+
+	if _, err := tx.ExecContext(ctx, query, statusPageID); err != nil {
+		return err
+	}
+
+	if len(monitorIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(monitorIDs))
+	args := []any{statusPageID}
+	for i, monitorID := range monitorIDs {
+		args = append(args, monitorID)
+		values[i] = fmt.Sprintf("($1, $%d)", len(args))
+	}
+
+	query = `
+    INSERT INTO status_page_monitors (status_page_id, monitor_id)
+    VALUES ` + strings.Join(values, ", ") + `;`
+
+	_, err := tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// loadMonitorIDs fills in the monitors attached to statusPages.
+func (s *StatusPagesStore) loadMonitorIDs(ctx context.Context, statusPages ...*StatusPage) error {
+	if len(statusPages) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*StatusPage, len(statusPages))
+	placeholders := make([]string, len(statusPages))
+	args := make([]any, len(statusPages))
+	for i, statusPage := range statusPages {
+		statusPage.MonitorIDs = []string{}
+		byID[statusPage.ID] = statusPage
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = statusPage.ID
+	}
+
+	query := `
+    SELECT status_page_id, monitor_id
+    FROM status_page_monitors
+    WHERE status_page_id IN (` + strings.Join(placeholders, ", ") + `)
+    ORDER BY rowid;`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch status page monitors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var statusPageID, monitorID string
+		if err := rows.Scan(&statusPageID, &monitorID); err != nil {
+			return fmt.Errorf("failed to scan status page monitor: %w", err)
+		}
+		byID[statusPageID].MonitorIDs = append(byID[statusPageID].MonitorIDs, monitorID)
+	}
+
+	return rows.Err()
+}
+
+func (s *StatusPagesStore) GetByID(ctx context.Context, id string) (*StatusPage, error) {
+	return s.get(ctx, "id = $1", id)
+}
+
+func (s *StatusPagesStore) GetBySlug(ctx context.Context, slug string) (*StatusPage, error) {
+	return s.get(ctx, "slug = $1", slug)
+}
+
+// GetByHostname returns the status page that has verified hostname.
+func (s *StatusPagesStore) GetByHostname(ctx context.Context, hostname string) (*StatusPage, error) {
+	return s.get(ctx, "hostname = $1 AND hostname_verified_at IS NOT NULL", hostname)
+}
+
+func (s *StatusPagesStore) get(ctx context.Context, condition, value string) (*StatusPage, error) {
+	query := `
+    SELECT ` + statusPageColumns + `
+    FROM status_pages
+    WHERE ` + condition + `;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var statusPage StatusPage
+
+	err := scanStatusPage(s.db.QueryRowContext(ctx, query, value), &statusPage)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if err := s.loadMonitorIDs(ctx, &statusPage); err != nil {
+		return nil, err
+	}
+
+	return &statusPage, nil
+}
+
+func (s *StatusPagesStore) List(ctx context.Context, userID string) ([]*StatusPage, error) {
+	query := `
+    SELECT ` + statusPageColumns + `
+    FROM status_pages
+    WHERE user_id = $1
+    ORDER BY created_at, id;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status pages: %w", err)
+	}
+	defer rows.Close()
+
+	statusPages := []*StatusPage{}
+	for rows.Next() {
+		var statusPage StatusPage
+		if err := scanStatusPage(rows, &statusPage); err != nil {
+			return nil, fmt.Errorf("failed to scan status page: %w", err)
+		}
+		statusPages = append(statusPages, &statusPage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	if err := s.loadMonitorIDs(ctx, statusPages...); err != nil {
+		return nil, err
+	}
+
+	return statusPages, nil
+}
+
+// ListByMonitorID returns the status pages that show the monitor.
+func (s *StatusPagesStore) ListByMonitorID(ctx context.Context, monitorID string) ([]*StatusPage, error) {
+	query := `
+    SELECT ` + statusPageColumns + `
+    FROM status_pages
+    WHERE id IN (
+      SELECT status_page_id
+      FROM status_page_monitors
+      WHERE monitor_id = $1
+    )
+    ORDER BY created_at, id;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status pages: %w", err)
+	}
+	defer rows.Close()
+
+	statusPages := []*StatusPage{}
+	for rows.Next() {
+		var statusPage StatusPage
+		if err := scanStatusPage(rows, &statusPage); err != nil {
+			return nil, fmt.Errorf("failed to scan status page: %w", err)
+		}
+		statusPages = append(statusPages, &statusPage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	if err := s.loadMonitorIDs(ctx, statusPages...); err != nil {
+		return nil, err
+	}
+
+	return statusPages, nil
+}
+
+// Update saves the name, slug, protection, hostname and monitors of
+// statusPage.
+func (s *StatusPagesStore) Update(ctx context.Context, statusPage *StatusPage) error {
+	query := `
+    UPDATE status_pages
+    SET name = $1, slug = $2, password_hash = $3, allowed_ips = $4, hostname = $5, hostname_verified_at = $6, hostname_token = $7
+    WHERE id = $8
+    RETURNING updated_at;
+  `
+
+	allowedIPs, err := nullableAllowedIPs(statusPage.AllowedIPs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkUnique(ctx, tx, statusPage); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		statusPage.Name,
+		statusPage.Slug,
+		statusPage.Password.hash,
+		allowedIPs,
+		nullableString(statusPage.Hostname),
+		nullableTime(statusPage.HostnameVerifiedAt),
+		nullableString(statusPage.HostnameToken),
+		statusPage.ID).Scan(&statusPage.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	if err := setStatusPageMonitors(ctx, tx, statusPage.ID, statusPage.MonitorIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *StatusPagesStore) Delete(ctx context.Context, id string) error {
+	query := `
+    DELETE FROM status_pages
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
 	return nil
 }
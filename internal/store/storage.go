@@ -12,11 +12,21 @@ var (
 	QueryTimeoutDuration = time.Second * 5
 )
 
+// timeFormat matches the format of SQLite's CURRENT_TIMESTAMP, so timestamps
+// written by the application compare correctly with column defaults.
+const timeFormat = time.DateTime
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
 type Storage struct {
 	Monitors interface {
 		Create(context.Context, *Monitor) error
 		GetByID(context.Context, string) (*Monitor, error)
+		GetByPushToken(context.Context, string) (*Monitor, error)
 		List(context.Context) ([]*Monitor, error)
+		ListByIDs(context.Context, []string) ([]*Monitor, error)
 		Delete(context.Context, string) error
 		Update(context.Context, *Monitor) error
 	}
@@ -25,17 +35,87 @@ type Storage struct {
 	}
 	PingResults interface {
 		Create(context.Context, *PingResult) error
+		CreateBatch(context.Context, []*PingResult) error
+		GetLatestConfirmed(context.Context, string) (*PingResult, error)
+		List(context.Context, PingResultFilter) ([]*PingResult, error)
+		Summarize(context.Context, string, time.Time, time.Time) (*PingResultSummary, error)
+		GetOldestTimestamp(context.Context) (time.Time, error)
+		DeleteBefore(context.Context, string, time.Time, int) (int64, error)
+		DeleteOrphans(context.Context, int) (int64, error)
 	}
 	StatusPages interface {
 		Create(context.Context, *StatusPage) error
+		GetByID(context.Context, string) (*StatusPage, error)
+		GetBySlug(context.Context, string) (*StatusPage, error)
+		GetByHostname(context.Context, string) (*StatusPage, error)
+		List(context.Context, string) ([]*StatusPage, error)
+		ListByMonitorID(context.Context, string) ([]*StatusPage, error)
+		Update(context.Context, *StatusPage) error
+		Delete(context.Context, string) error
+	}
+	Incidents interface {
+		Create(context.Context, *Incident) error
+		GetOpenByMonitorID(context.Context, string) (*Incident, error)
+		Update(context.Context, *Incident) error
+		List(context.Context, IncidentFilter) ([]*Incident, error)
+	}
+	Certificates interface {
+		Upsert(context.Context, *Certificate) error
+		GetByMonitorID(context.Context, string) (*Certificate, error)
+	}
+	Posts interface {
+		Create(context.Context, *Post) error
+		GetByID(context.Context, string) (*Post, error)
+		List(context.Context, PostFilter) ([]*Post, error)
+		Update(context.Context, *Post) error
+		AddUpdate(context.Context, *Post, *PostUpdate) error
+		Delete(context.Context, string) error
+		ListMaintenanceWindows(context.Context, string, time.Time, time.Time) ([]MaintenanceWindow, error)
+	}
+	ComponentGroups interface {
+		Create(context.Context, *ComponentGroup) error
+		GetByID(context.Context, string) (*ComponentGroup, error)
+		List(context.Context, string) ([]*ComponentGroup, error)
+		Update(context.Context, *ComponentGroup) error
+		Delete(context.Context, string) error
+	}
+	Components interface {
+		Create(context.Context, *Component) error
+		GetByID(context.Context, string) (*Component, error)
+		List(context.Context, string) ([]*Component, error)
+		Update(context.Context, *Component) error
+		Delete(context.Context, string) error
+	}
+	Subscribers interface {
+		Create(context.Context, *Subscriber) error
+		GetByID(context.Context, string) (*Subscriber, error)
+		GetByTarget(context.Context, string, string, string) (*Subscriber, error)
+		List(context.Context, SubscriberFilter) ([]*Subscriber, error)
+		Confirm(context.Context, *Subscriber) error
+		MarkConfirmationSent(context.Context, *Subscriber, time.Time) (bool, error)
+		Delete(context.Context, string) error
+	}
+	Rollups interface {
+		List(context.Context, RollupFilter) ([]*Rollup, error)
+		Save(context.Context, string, []*Rollup, time.Time) error
+		Watermarks(context.Context) (map[string]time.Time, error)
+		DeleteBefore(context.Context, string, string, time.Time, int) (int64, error)
+		DeleteOrphans(context.Context, string, int) (int64, error)
 	}
 }
 
 func NewStorage(db *sql.DB) Storage {
 	return Storage{
-		Monitors:    &MonitorStore{db},
-		Users:       &UsersStore{db},
-		PingResults: &PingResultStore{db},
-		StatusPages: &StatusPagesStore{db},
+		Monitors:        &MonitorStore{db},
+		Users:           &UsersStore{db},
+		PingResults:     &PingResultStore{db},
+		StatusPages:     &StatusPagesStore{db},
+		Certificates:    &CertificateStore{db},
+		Incidents:       &IncidentStore{db},
+		Rollups:         &RollupStore{db},
+		Posts:           &PostStore{db},
+		Subscribers:     &SubscriberStore{db},
+		ComponentGroups: &ComponentGroupStore{db},
+		Components:      &ComponentStore{db},
 	}
 }
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	SubscriberEmail   = "email"
+	SubscriberWebhook = "webhook"
+)
+
+var ErrDuplicateSubscriber = errors.New("already subscribed to this status page")
+
+// Subscriber is notified about a status page by email or webhook. Target is
+// the email address or the webhook URL.
+type Subscriber struct {
+	ID           string `json:"id"`
+	StatusPageID string `json:"status_page_id"`
+	Kind         string `json:"kind"`
+	Target       string `json:"target"`
+	// ConfirmedAt is nil until an email subscriber has opted in. Webhooks
+	// are confirmed when they are created.
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   string     `json:"created_at"`
+}
+
+// SubscriberFilter narrows down listed subscribers. Zero values do not
+// filter.
+type SubscriberFilter struct {
+	StatusPageID string
+	Confirmed    bool
+}
+
+type SubscriberStore struct {
+	db *sql.DB
+}
+
+const subscriberColumns = `id, status_page_id, kind, target, confirmed_at, created_at`
+
+func scanSubscriber(row interface{ Scan(...any) error }, subscriber *Subscriber) error {
+	return row.Scan(
+		&subscriber.ID,
+		&subscriber.StatusPageID,
+		&subscriber.Kind,
+		&subscriber.Target,
+		&subscriber.ConfirmedAt,
+		&subscriber.CreatedAt,
+	)
+}
+
+func (s *SubscriberStore) Create(ctx context.Context, subscriber *Subscriber) error {
+	query := `
+    INSERT INTO status_page_subscribers (id, status_page_id, kind, target, confirmed_at)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING created_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(
+		ctx,
+		query,
+		subscriber.ID,
+		subscriber.StatusPageID,
+		subscriber.Kind,
+		subscriber.Target,
+		nullableTime(subscriber.ConfirmedAt),
+	).Scan(&subscriber.CreatedAt)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrDuplicateSubscriber
+	}
+
+	return err
+}
+
+func (s *SubscriberStore) GetByID(ctx context.Context, id string) (*Subscriber, error) {
+	query := `
+    SELECT ` + subscriberColumns + `
+    FROM status_page_subscribers
+    WHERE id = $1;
+  `
+
+	return s.get(ctx, query, id)
+}
+
+// GetByTarget returns the subscriber of a status page with the given kind and
+// target.
+func (s *SubscriberStore) GetByTarget(ctx context.Context, statusPageID, kind, target string) (*Subscriber, error) {
+	query := `
+    SELECT ` + subscriberColumns + `
+    FROM status_page_subscribers
+    WHERE status_page_id = $1 AND kind = $2 AND target = $3;
+  `
+
+	return s.get(ctx, query, statusPageID, kind, target)
+}
+
+func (s *SubscriberStore) get(ctx context.Context, query string, args ...any) (*Subscriber, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var subscriber Subscriber
+
+	err := scanSubscriber(s.db.QueryRowContext(ctx, query, args...), &subscriber)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &subscriber, nil
+}
+
+func (s *SubscriberStore) List(ctx context.Context, filter SubscriberFilter) ([]*Subscriber, error) {
+	var conditions []string
+	var args []any
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.StatusPageID != "" {
+		where("status_page_id = $%d", filter.StatusPageID)
+	}
+
+	if filter.Confirmed {
+		conditions = append(conditions, "confirmed_at IS NOT NULL")
+	}
+
+	query := `
+    SELECT ` + subscriberColumns + `
+    FROM status_page_subscribers`
+
+	if len(conditions) > 0 {
+		query += `
+    WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	query += `
+    ORDER BY created_at, id;`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	subscribers := []*Subscriber{}
+	for rows.Next() {
+		var subscriber Subscriber
+		if err := scanSubscriber(rows, &subscriber); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		subscribers = append(subscribers, &subscriber)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+// Confirm records that subscriber has opted in, unless it already has.
+func (s *SubscriberStore) Confirm(ctx context.Context, subscriber *Subscriber) error {
+	query := `
+    UPDATE status_page_subscribers
+    SET confirmed_at = COALESCE(confirmed_at, $1)
+    WHERE id = $2
+    RETURNING confirmed_at;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, formatTime(time.Now()), subscriber.ID).Scan(&subscriber.ConfirmedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkConfirmationSent records that the unconfirmed subscriber is being sent
+// the link to confirm their address, unless they were already sent one after
+// since. It reports whether the link should be sent.
+func (s *SubscriberStore) MarkConfirmationSent(ctx context.Context, subscriber *Subscriber, since time.Time) (bool, error) {
+	query := `
+    UPDATE status_page_subscribers
+    SET confirmation_sent_at = $1
+    WHERE id = $2
+      AND confirmed_at IS NULL
+      AND (confirmation_sent_at IS NULL OR confirmation_sent_at <= $3);
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, formatTime(time.Now()), subscriber.ID, formatTime(since))
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (s *SubscriberStore) Delete(ctx context.Context, id string) error {
+	query := `
+    DELETE FROM status_page_subscribers
+    WHERE id = $1;
+  `
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
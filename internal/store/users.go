@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -32,6 +33,38 @@ func (p *password) Set(text string) error {
 	return nil
 }
 
+// Matches reports whether text is the password.
+func (p *password) Matches(text string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword(p.hash, []byte(text))
+	if err != nil {
+		switch {
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// IsSet reports whether there is a password.
+func (p *password) IsSet() bool {
+	return len(p.hash) > 0
+}
+
+// Hash returns the bcrypt hash of the password. It changes whenever the
+// password is set, even to the same text.
+func (p *password) Hash() []byte {
+	return p.hash
+}
+
+// Clear removes the password.
+func (p *password) Clear() {
+	p.text = nil
+	p.hash = nil
+}
+
 type UsersStore struct {
 	db *sql.DB
 }